@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/pricefeed"
+	"github.com/ma3xco/token-listing/pkg/registry"
+	"github.com/ma3xco/token-listing/pkg/signing"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	var addr string
+	var location string
+	var publicKeyFile string
+	var refresh time.Duration
+	var interval time.Duration
+	var maxBackoff time.Duration
+	var timeout time.Duration
+	var maxAge time.Duration
+
+	flag.StringVar(&addr, "addr", ":8081", "The address the proxy listens on")
+	flag.StringVar(&location, "registry", "https://ma3xco.github.io/token-listing", "The registry the tokens are read from, an http(s) URL or a local dist directory")
+	flag.StringVar(&publicKeyFile, "public-key", "", "Path of the PEM encoded ed25519 public key the registry manifest signature is verified with, the manifest is not verified if empty")
+	flag.DurationVar(&refresh, "refresh", 5*time.Minute, "How often the registry is checked for new tokens")
+	flag.DurationVar(&interval, "interval", time.Minute, "How often every live price url is polled")
+	flag.DurationVar(&maxBackoff, "max-backoff", 30*time.Minute, "The longest delay before a failing live price url is polled again")
+	flag.DurationVar(&timeout, "timeout", pricefeed.DefaultTimeout, "The time limit of a live price url poll")
+	flag.DurationVar(&maxAge, "max-age", pricefeed.DefaultMaxAge, "The age above which a price is reported as stale")
+	flag.Parse()
+
+	if interval <= 0 || refresh <= 0 || maxBackoff < interval {
+		log.Fatalf("-interval and -refresh must be positive, and -max-backoff at least -interval")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var opts []registry.Option
+	if publicKeyFile != "" {
+		pemKey, err := os.ReadFile(publicKeyFile)
+		if err != nil {
+			log.Fatalf("failed to read the public key: %v", err)
+		}
+		publicKey, err := signing.ParsePublicKey(pemKey)
+		if err != nil {
+			log.Fatalf("failed to parse the public key: %v", err)
+		}
+		opts = append(opts, registry.WithPublicKey(publicKey))
+	}
+
+	logger := logrus.New()
+	client, err := registry.New(ctx, location, opts...)
+	if err != nil {
+		log.Fatalf("failed to load the registry: %v", err)
+	}
+	checker := pricefeed.New(pricefeed.WithTimeout(timeout), pricefeed.WithMaxAge(maxAge))
+	p := newPoller(logger, checker, interval, maxBackoff)
+	p.setTokens(client.Tokens())
+	go p.run(ctx)
+	go refreshRegistry(ctx, logger, client, p, refresh)
+
+	s := &server{logger: logger, poller: p, maxAge: maxAge}
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+	logger.Infof("serving the prices of %s on %s", location, addr)
+	err = httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
+
+// refreshRegistry reloads the registry every interval and hands the changed tokens to the poller,
+// a failed refresh keeps the previous tokens and is retried on the next interval.
+func refreshRegistry(ctx context.Context, logger logrus.FieldLogger, client *registry.Client, p *poller, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := client.Refresh(ctx)
+		if err != nil {
+			logger.Errorf("failed to refresh the registry, polling the previous tokens: %v", err)
+			continue
+		}
+		if changed {
+			p.setTokens(client.Tokens())
+			logger.Infof("reloaded the registry")
+		}
+	}
+}
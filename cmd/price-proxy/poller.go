@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/ma3xco/token-listing/internal/pricefeed"
+	"github.com/sirupsen/logrus"
+)
+
+// maxConcurrentPolls is the number of the live price urls polled at the same time.
+const maxConcurrentPolls = 8
+
+// feed is the cached state of a live price url.
+type feed struct {
+	// the last valid price, nil until the first successful poll.
+	price *models.LivePrice
+
+	// the time of the last successful poll.
+	fetchedAt time.Time
+
+	// the problems of the last poll, empty if it had none.
+	problems []string
+
+	// the number of the failed polls in a row, the backoff grows with it.
+	failures int
+
+	// the time of the next poll.
+	nextPoll time.Time
+}
+
+// poller polls the live price urls of the tokens and caches the last valid prices.
+// the urls shared by several tokens are polled once.
+type poller struct {
+	logger     logrus.FieldLogger
+	checker    *pricefeed.Checker
+	interval   time.Duration
+	maxBackoff time.Duration
+	now        func() time.Time
+
+	mu sync.RWMutex
+	// the key is the token uid, the value is its live price url.
+	urls map[string]string
+	// the key is the live price url.
+	feeds map[string]*feed
+}
+
+func newPoller(logger logrus.FieldLogger, checker *pricefeed.Checker, interval, maxBackoff time.Duration) *poller {
+	return &poller{
+		logger:     logger,
+		checker:    checker,
+		interval:   interval,
+		maxBackoff: maxBackoff,
+		now:        time.Now,
+		urls:       make(map[string]string),
+		feeds:      make(map[string]*feed),
+	}
+}
+
+// setTokens replaces the polled tokens with the tokens that have a live price url,
+// the cached prices of the urls still in use are kept.
+func (p *poller) setTokens(tokens []models.Token) {
+	urls := make(map[string]string)
+	for _, token := range tokens {
+		if token.LivePriceUrl != "" {
+			urls[token.Uuid] = token.LivePriceUrl
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	feeds := make(map[string]*feed)
+	for _, url := range urls {
+		if f, ok := p.feeds[url]; ok {
+			feeds[url] = f
+		} else {
+			feeds[url] = &feed{}
+		}
+	}
+	p.urls = urls
+	p.feeds = feeds
+}
+
+// run polls the urls that are due every second until the context is done.
+func (p *poller) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		p.pollDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollDue polls the urls whose next poll time has passed.
+func (p *poller) pollDue(ctx context.Context) {
+	now := p.now()
+	p.mu.RLock()
+	var due []string
+	for url, f := range p.feeds {
+		if !now.Before(f.nextPoll) {
+			due = append(due, url)
+		}
+	}
+	p.mu.RUnlock()
+	sort.Strings(due)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentPolls)
+	for _, url := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			p.poll(ctx, url)
+		}()
+	}
+	wg.Wait()
+}
+
+// poll fetches the url and updates its feed, a failed poll keeps the cached price
+// and backs off exponentially up to the max backoff.
+func (p *poller) poll(ctx context.Context, url string) {
+	price, problems := p.checker.Check(ctx, url)
+	if ctx.Err() != nil {
+		return
+	}
+	now := p.now()
+	messages := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f, ok := p.feeds[url]
+	if !ok {
+		// the url was dropped by a registry refresh while it was polled.
+		return
+	}
+	f.problems = messages
+	// a stale price is still the latest price of the url, the staleness is reported with it.
+	if price != nil {
+		f.price = price
+		f.fetchedAt = now
+		f.failures = 0
+		f.nextPoll = now.Add(p.interval)
+		return
+	}
+	f.failures++
+	f.nextPoll = now.Add(p.backoff(f.failures))
+	p.logger.Warnf("failed to poll %s (%d in a row): %v", url, f.failures, errors.Join(problems...))
+}
+
+// backoff returns the delay after the failed polls in a row, the interval doubled
+// for every failure up to the max backoff.
+func (p *poller) backoff(failures int) time.Duration {
+	delay := p.interval
+	for range failures {
+		delay *= 2
+		if delay >= p.maxBackoff {
+			return p.maxBackoff
+		}
+	}
+	return delay
+}
+
+// lookup returns a copy of the feed of the token, it returns false if the token has no live price url.
+func (p *poller) lookup(uid string) (feed, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	url, ok := p.urls[uid]
+	if !ok {
+		return feed{}, false
+	}
+	return *p.feeds[url], true
+}
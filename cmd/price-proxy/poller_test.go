@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/ma3xco/token-listing/internal/pricefeed"
+	"github.com/sirupsen/logrus"
+)
+
+// testNow is the time of the pollers and checkers of the tests.
+var testNow = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// priceURL is a fake live price url whose response can be changed between the polls.
+type priceURL struct {
+	mu     sync.Mutex
+	status int
+	body   string
+}
+
+func (u *priceURL) set(status int, body string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.status, u.body = status, body
+}
+
+func (u *priceURL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	w.WriteHeader(u.status)
+	_, _ = io.WriteString(w, u.body)
+}
+
+func newTestPoller(interval, maxBackoff time.Duration) *poller {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	checker := pricefeed.New(pricefeed.WithClock(func() time.Time { return testNow }))
+	p := newPoller(logger, checker, interval, maxBackoff)
+	p.now = func() time.Time { return testNow }
+	return p
+}
+
+func TestPoll(t *testing.T) {
+	u := &priceURL{status: http.StatusOK, body: `{"price_usd": 2.5, "last_updated": "2026-01-01T11:59:00Z"}`}
+	server := httptest.NewServer(u)
+	t.Cleanup(server.Close)
+
+	p := newTestPoller(time.Minute, 30*time.Minute)
+	p.setTokens([]models.Token{{Uuid: "a", LivePriceUrl: server.URL}})
+	p.poll(context.Background(), server.URL)
+	f, ok := p.lookup("a")
+	if !ok || f.price == nil || f.price.PriceUsd != 2.5 {
+		t.Fatalf("lookup() after a successful poll = %+v, %v, want price_usd 2.5", f, ok)
+	}
+	if f.failures != 0 || len(f.problems) != 0 || !f.fetchedAt.Equal(testNow) || !f.nextPoll.Equal(testNow.Add(time.Minute)) {
+		t.Errorf("feed after a successful poll = %+v, want no failures and the next poll after the interval", f)
+	}
+
+	// a failing url keeps its last valid price and backs off.
+	u.set(http.StatusBadGateway, "")
+	for failures, delay := range []time.Duration{2 * time.Minute, 4 * time.Minute, 8 * time.Minute} {
+		p.poll(context.Background(), server.URL)
+		f, _ = p.lookup("a")
+		if f.failures != failures+1 || !f.nextPoll.Equal(testNow.Add(delay)) {
+			t.Errorf("feed after %d failed polls = failures %d, next poll %v, want the next poll after %s",
+				failures+1, f.failures, f.nextPoll, delay)
+		}
+		if f.price == nil || f.price.PriceUsd != 2.5 || len(f.problems) != 1 {
+			t.Errorf("feed after a failed poll = %+v, want the cached price and the problem", f)
+		}
+	}
+
+	// a stale price is still a price, the failures are reset and the staleness is reported.
+	u.set(http.StatusOK, `{"price_usd": 3, "last_updated": "2026-01-01T09:00:00Z"}`)
+	p.poll(context.Background(), server.URL)
+	f, _ = p.lookup("a")
+	if f.price == nil || f.price.PriceUsd != 3 || f.failures != 0 || len(f.problems) != 1 {
+		t.Errorf("feed after a stale poll = %+v, want the stale price, no failures and the staleness problem", f)
+	}
+
+	// an invalid response is a failure.
+	u.set(http.StatusOK, `{"price_usd": "3"}`)
+	p.poll(context.Background(), server.URL)
+	f, _ = p.lookup("a")
+	if f.price == nil || f.price.PriceUsd != 3 || f.failures != 1 {
+		t.Errorf("feed after an invalid response = %+v, want the cached price and a failure", f)
+	}
+}
+
+func TestPollDroppedURL(t *testing.T) {
+	u := &priceURL{status: http.StatusOK, body: `{"price_usd": 1}`}
+	server := httptest.NewServer(u)
+	t.Cleanup(server.Close)
+
+	p := newTestPoller(time.Minute, time.Hour)
+	p.poll(context.Background(), server.URL)
+	if len(p.feeds) != 0 {
+		t.Errorf("poll() of a url without tokens created a feed: %v", p.feeds)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := newTestPoller(time.Minute, 30*time.Minute)
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, 2 * time.Minute},
+		{2, 4 * time.Minute},
+		{4, 16 * time.Minute},
+		{5, 30 * time.Minute},
+		{100, 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := p.backoff(tt.failures); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestSetTokens(t *testing.T) {
+	p := newTestPoller(time.Minute, time.Hour)
+	p.setTokens([]models.Token{
+		{Uuid: "a", LivePriceUrl: "https://prices.example/shared"},
+		{Uuid: "b", LivePriceUrl: "https://prices.example/shared"},
+		{Uuid: "c", LivePriceUrl: "https://prices.example/c"},
+		{Uuid: "d"},
+	})
+	if len(p.feeds) != 2 {
+		t.Fatalf("setTokens() created %d feeds, want 2, the shared url is polled once", len(p.feeds))
+	}
+	if _, ok := p.lookup("d"); ok {
+		t.Errorf("lookup() found a token without a live price url")
+	}
+	cached := &models.LivePrice{PriceUsd: 1}
+	p.feeds["https://prices.example/shared"].price = cached
+	p.feeds["https://prices.example/shared"].failures = 3
+
+	p.setTokens([]models.Token{
+		{Uuid: "a", LivePriceUrl: "https://prices.example/shared"},
+		{Uuid: "e", LivePriceUrl: "https://prices.example/e"},
+	})
+	if f, ok := p.lookup("a"); !ok || f.price != cached || f.failures != 3 {
+		t.Errorf("lookup() of a kept url = %+v, %v, want the cached feed", f, ok)
+	}
+	if f, ok := p.lookup("e"); !ok || f.price != nil {
+		t.Errorf("lookup() of a new url = %+v, %v, want an empty feed", f, ok)
+	}
+	for _, uid := range []string{"b", "c"} {
+		if _, ok := p.lookup(uid); ok {
+			t.Errorf("lookup(%q) found a removed token", uid)
+		}
+	}
+	if _, ok := p.feeds["https://prices.example/c"]; ok {
+		t.Errorf("setTokens() kept the feed of a url no longer in use")
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// maxUids is the limit of the uids of a request.
+const maxUids = 200
+
+// server serves the cached prices of the poller.
+type server struct {
+	logger logrus.FieldLogger
+	poller *poller
+
+	// the age above which a price is reported as stale.
+	maxAge time.Duration
+}
+
+// pricesResponse is the response of the prices endpoint.
+type pricesResponse struct {
+	// The prices by the token uid.
+	Prices map[string]priceStatus `json:"prices"`
+
+	// The requested uids that are not in the registry or have no live price url.
+	Missing []string `json:"missing"`
+
+	// The age above which a price is stale, in seconds.
+	MaxAgeSeconds int64 `json:"max_age_seconds"`
+}
+
+// priceStatus is the cached price of a token with its staleness.
+type priceStatus struct {
+	// The last valid price normalized to the live price contract, null until the first successful poll.
+	Price *models.LivePrice `json:"price"`
+
+	// The time of the last successful poll, null until then.
+	FetchedAt *time.Time `json:"fetched_at"`
+
+	// The age of the price in seconds, from its last_updated or else from the poll time, null without a price.
+	AgeSeconds *int64 `json:"age_seconds"`
+
+	// Whether there is no price or it is older than max_age_seconds.
+	Stale bool `json:"stale"`
+
+	// The problems of the last poll, e.g. a failed request or an invalid response.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// errorResponse is the response of the failed requests.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// handler returns the routes of the proxy:
+// - GET /prices?uids= (the comma-separated token uids)
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /prices", s.prices)
+	return mux
+}
+
+func (s *server) prices(w http.ResponseWriter, r *http.Request) {
+	var uids []string
+	for uid := range strings.SplitSeq(r.URL.Query().Get("uids"), ",") {
+		if uid = strings.TrimSpace(uid); uid != "" {
+			uids = append(uids, uid)
+		}
+	}
+	if len(uids) == 0 {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("uids is required"))
+		return
+	}
+	if len(uids) > maxUids {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("too many uids, the limit is "+strconv.Itoa(maxUids)))
+		return
+	}
+
+	now := s.poller.now()
+	response := pricesResponse{
+		Prices:        make(map[string]priceStatus),
+		Missing:       []string{},
+		MaxAgeSeconds: int64(s.maxAge / time.Second),
+	}
+	for _, uid := range uids {
+		f, ok := s.poller.lookup(uid)
+		if !ok {
+			response.Missing = append(response.Missing, uid)
+			continue
+		}
+		status := priceStatus{Price: f.price, Stale: true, Errors: f.problems}
+		if f.price != nil {
+			fetchedAt := f.fetchedAt
+			status.FetchedAt = &fetchedAt
+			updated := fetchedAt
+			if f.price.LastUpdated != nil {
+				updated = *f.price.LastUpdated
+			}
+			age := int64(max(now.Sub(updated), 0) / time.Second)
+			status.AgeSeconds = &age
+			status.Stale = now.Sub(updated) > s.maxAge
+		}
+		response.Prices[uid] = status
+	}
+	s.writeJSON(w, r, response)
+}
+
+// writeJSON writes the value as JSON, the prices are never cached by the clients.
+func (s *server) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_, err = w.Write(body)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		s.logger.Warnf("failed to write the response of %s: %v", r.URL.Path, err)
+	}
+}
+
+// writeError writes the error as JSON with the status code.
+func (s *server) writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		s.logger.Errorf("failed to serve %s: %v", r.URL.Path, err)
+	}
+	body, _ := json.Marshal(errorResponse{Error: err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestPrices(t *testing.T) {
+	fresh := testNow.Add(-10 * time.Minute)
+	stale := testNow.Add(-2 * time.Hour)
+	volume := 1000.0
+
+	p := newTestPoller(time.Minute, time.Hour)
+	p.setTokens([]models.Token{
+		{Uuid: "fresh", LivePriceUrl: "https://prices.example/fresh"},
+		{Uuid: "stale", LivePriceUrl: "https://prices.example/stale"},
+		{Uuid: "polled", LivePriceUrl: "https://prices.example/polled"},
+		{Uuid: "pending", LivePriceUrl: "https://prices.example/pending"},
+	})
+	p.feeds["https://prices.example/fresh"].price = &models.LivePrice{PriceUsd: 1, Volume24h: &volume, LastUpdated: &fresh}
+	p.feeds["https://prices.example/fresh"].fetchedAt = testNow.Add(-time.Minute)
+	p.feeds["https://prices.example/stale"].price = &models.LivePrice{PriceUsd: 2, LastUpdated: &stale}
+	p.feeds["https://prices.example/stale"].fetchedAt = testNow.Add(-time.Minute)
+	p.feeds["https://prices.example/stale"].problems = []string{"unexpected status 502 Bad Gateway"}
+	// without last_updated the age is measured from the poll.
+	p.feeds["https://prices.example/polled"].price = &models.LivePrice{PriceUsd: 3}
+	p.feeds["https://prices.example/polled"].fetchedAt = testNow.Add(-90 * time.Minute)
+	p.feeds["https://prices.example/pending"].problems = []string{"request failed"}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &server{logger: logger, poller: p, maxAge: time.Hour}
+	req := httptest.NewRequest(http.MethodGet, "/prices?uids=fresh,stale,%20polled,pending,unknown,", nil)
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /prices status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+
+	var raw struct {
+		Prices map[string]map[string]json.RawMessage `json:"prices"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	price := string(raw.Prices["fresh"]["price"])
+	if !strings.Contains(price, `"volume_24h":1000`) || strings.Contains(price, "percent_change") {
+		t.Errorf("fresh price = %s, want volume_24h and without the omitted fields", price)
+	}
+
+	var response pricesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.MaxAgeSeconds != 3600 {
+		t.Errorf("max_age_seconds = %d, want 3600", response.MaxAgeSeconds)
+	}
+	if len(response.Missing) != 1 || response.Missing[0] != "unknown" {
+		t.Errorf("missing = %v, want [unknown]", response.Missing)
+	}
+	tests := []struct {
+		uid        string
+		wantPrice  float64
+		wantAge    int64
+		wantStale  bool
+		wantErrors int
+	}{
+		{"fresh", 1, 600, false, 0},
+		{"stale", 2, 7200, true, 1},
+		{"polled", 3, 5400, true, 0},
+		{"pending", 0, -1, true, 1},
+	}
+	for _, tt := range tests {
+		status, ok := response.Prices[tt.uid]
+		if !ok {
+			t.Errorf("prices has no %q", tt.uid)
+			continue
+		}
+		if tt.wantAge < 0 {
+			if status.Price != nil || status.FetchedAt != nil || status.AgeSeconds != nil {
+				t.Errorf("prices[%q] = %+v, want no price, fetched_at and age_seconds", tt.uid, status)
+			}
+		} else {
+			if status.Price == nil || status.Price.PriceUsd != tt.wantPrice {
+				t.Errorf("prices[%q].price = %+v, want price_usd %v", tt.uid, status.Price, tt.wantPrice)
+			}
+			if status.FetchedAt == nil || status.AgeSeconds == nil || *status.AgeSeconds != tt.wantAge {
+				t.Errorf("prices[%q] fetched_at = %v, age_seconds = %v, want the age %d", tt.uid, status.FetchedAt, status.AgeSeconds, tt.wantAge)
+			}
+		}
+		if status.Stale != tt.wantStale {
+			t.Errorf("prices[%q].stale = %v, want %v", tt.uid, status.Stale, tt.wantStale)
+		}
+		if len(status.Errors) != tt.wantErrors {
+			t.Errorf("prices[%q].errors = %v, want %d errors", tt.uid, status.Errors, tt.wantErrors)
+		}
+	}
+}
+
+func TestPricesBadRequest(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &server{logger: logger, poller: newTestPoller(time.Minute, time.Hour), maxAge: time.Hour}
+	for _, target := range []string{
+		"/prices",
+		"/prices?uids=,,",
+		"/prices?uids=" + strings.Repeat("a,", maxUids+1),
+	} {
+		rec := httptest.NewRecorder()
+		s.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want 400", target[:min(len(target), 32)], rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/prices?uids=a", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /prices status = %d, want 405", rec.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tokenmanager "github.com/ma3xco/token-listing/internal/token_manager"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	var addr string
+	var watch bool
+	var interval time.Duration
+
+	flag.StringVar(&addr, "addr", ":8080", "The address the API listens on")
+	flag.BoolVar(&watch, "watch", false, "Whether the registry is reloaded when tokens/ or networks/ change")
+	flag.DurationVar(&interval, "interval", 2*time.Second, "How often tokens/ and networks/ are checked for changes in the watch mode")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger := logrus.New()
+	tm, err := load(ctx)
+	if err != nil {
+		log.Fatalf("failed to load the registry: %v", err)
+	}
+	s := newServer(logger, tm)
+	if watch {
+		go s.watch(ctx, interval, s.reload, "tokens", "networks", "tags")
+	}
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+	logger.Infof("serving the registry on %s", addr)
+	err = httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
+
+// reload loads the registry again and swaps it in, a failed load keeps the current state.
+func (s *server) reload(ctx context.Context) error {
+	tm, err := load(ctx)
+	if err != nil {
+		return err
+	}
+	s.swap(tm)
+	return nil
+}
+
+// load loads the networks and the tokens into a new token manager.
+func load(ctx context.Context) (tokenmanager.ITokenManager, error) {
+	tm, err := tokenmanager.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tm.WalkThrough(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tm, nil
+}
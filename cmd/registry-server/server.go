@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	tokenmanager "github.com/ma3xco/token-listing/internal/token_manager"
+	"github.com/ma3xco/token-listing/pkg/search"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultPerPage is the page size when the per_page query parameter is not set.
+	defaultPerPage = 50
+
+	// maxPerPage is the limit of the per_page query parameter.
+	maxPerPage = 500
+)
+
+// server serves the registry API from the in-memory state of a token manager.
+// the token manager is swapped on reload, the handlers never see a half loaded state.
+type server struct {
+	logger logrus.FieldLogger
+
+	mu    sync.RWMutex
+	tm    tokenmanager.ITokenManager
+	index *search.Index
+}
+
+// tokenList is the response of the token list endpoints.
+type tokenList struct {
+	Tokens  []models.Token `json:"tokens"`
+	Page    int            `json:"page"`
+	PerPage int            `json:"per_page"`
+	Total   int            `json:"total"`
+}
+
+// errorResponse is the response of the failed requests.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func newServer(logger logrus.FieldLogger, tm tokenmanager.ITokenManager) *server {
+	s := &server{logger: logger}
+	s.swap(tm)
+	return s
+}
+
+// tokenManager returns the current token manager.
+func (s *server) tokenManager() tokenmanager.ITokenManager {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tm
+}
+
+// state returns the current token manager and its search index.
+func (s *server) state() (tokenmanager.ITokenManager, *search.Index) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tm, s.index
+}
+
+// swap replaces the token manager the requests are served from.
+func (s *server) swap(tm tokenmanager.ITokenManager) {
+	index := tm.SearchIndex(context.Background())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tm = tm
+	s.index = index
+}
+
+// handler returns the routes of the API:
+// - GET /v1/tokens (filters: network, tag, featured, stable, q; pagination: page, per_page)
+// - GET /v1/tokens/{uid}
+// - GET /v1/search?q= (the tokens matching q ranked by the search index, filtered and paginated as above)
+// - GET /v1/networks
+// - GET /v1/networks/{id}
+// - GET /v1/networks/{id}/tokens/{address}
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/tokens", s.listTokens)
+	mux.HandleFunc("GET /v1/tokens/{uid}", s.getToken)
+	mux.HandleFunc("GET /v1/search", s.search)
+	mux.HandleFunc("GET /v1/networks", s.listNetworks)
+	mux.HandleFunc("GET /v1/networks/{id}", s.getNetwork)
+	mux.HandleFunc("GET /v1/networks/{id}/tokens/{address}", s.getTokenByAddress)
+	return mux
+}
+
+func (s *server) listTokens(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	s.writeTokens(w, r, s.queryTokens(r.Context(), filter))
+}
+
+func (s *server) search(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if filter.Query == "" {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("q is required"))
+		return
+	}
+	s.writeTokens(w, r, s.queryTokens(r.Context(), filter))
+}
+
+// queryTokens returns the tokens matching the filter, the query is answered by the search index,
+// so the tokens are ranked by the relevance instead of the order index.
+func (s *server) queryTokens(ctx context.Context, filter tokenmanager.TokenFilter) []models.Token {
+	tm, index := s.state()
+	query := filter.Query
+	if query == "" {
+		return tm.ListTokens(ctx, filter)
+	}
+	filter.Query = ""
+	matching := make(map[string]models.Token)
+	for _, token := range tm.ListTokens(ctx, filter) {
+		matching[token.Uuid] = token
+	}
+	tokens := []models.Token{}
+	for _, result := range index.Search(query, 0) {
+		if token, ok := matching[result.Entry.Uid]; ok {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func (s *server) getToken(w http.ResponseWriter, r *http.Request) {
+	token, ok := s.tokenManager().GetToken(r.Context(), r.PathValue("uid"))
+	if !ok {
+		s.writeError(w, r, http.StatusNotFound, errors.New("token not found"))
+		return
+	}
+	s.writeJSON(w, r, token)
+}
+
+func (s *server) getTokenByAddress(w http.ResponseWriter, r *http.Request) {
+	networkId, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("invalid network id"))
+		return
+	}
+	token, ok := s.tokenManager().GetTokenByAddress(r.Context(), networkId, r.PathValue("address"))
+	if !ok {
+		s.writeError(w, r, http.StatusNotFound, errors.New("token not found"))
+		return
+	}
+	s.writeJSON(w, r, token)
+}
+
+func (s *server) listNetworks(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, r, s.tokenManager().ListNetworks(r.Context()))
+}
+
+func (s *server) getNetwork(w http.ResponseWriter, r *http.Request) {
+	networkId, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("invalid network id"))
+		return
+	}
+	for _, network := range s.tokenManager().ListNetworks(r.Context()) {
+		if network.Id == networkId {
+			s.writeJSON(w, r, network)
+			return
+		}
+	}
+	s.writeError(w, r, http.StatusNotFound, errors.New("network not found"))
+}
+
+// writeTokens writes the requested page of the tokens.
+func (s *server) writeTokens(w http.ResponseWriter, r *http.Request, tokens []models.Token) {
+	page, perPage, err := parsePage(r)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	start := min((page-1)*perPage, len(tokens))
+	end := min(start+perPage, len(tokens))
+	s.writeJSON(w, r, tokenList{
+		Tokens:  tokens[start:end],
+		Page:    page,
+		PerPage: perPage,
+		Total:   len(tokens),
+	})
+}
+
+// writeJSON writes the value as JSON with an ETag of its content,
+// it responds with 304 Not Modified if the ETag matches the If-None-Match header.
+func (s *server) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(body)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		s.logger.Warnf("failed to write the response of %s: %v", r.URL.Path, err)
+	}
+}
+
+// writeError writes the error as JSON with the status code.
+func (s *server) writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		s.logger.Errorf("failed to serve %s: %v", r.URL.Path, err)
+	}
+	body, _ := json.Marshal(errorResponse{Error: err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// etagMatches reports whether the If-None-Match header matches the ETag,
+// the weak comparison is used as for the GET requests (RFC 9110).
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFilter parses the token filter from the query parameters.
+func parseFilter(r *http.Request) (tokenmanager.TokenFilter, error) {
+	query := r.URL.Query()
+	filter := tokenmanager.TokenFilter{
+		Tag:   query.Get("tag"),
+		Query: query.Get("q"),
+	}
+	if network := query.Get("network"); network != "" {
+		networkId, err := strconv.ParseInt(network, 10, 64)
+		if err != nil || networkId <= 0 {
+			return filter, errors.New("invalid network")
+		}
+		filter.NetworkId = networkId
+	}
+	for name, target := range map[string]**bool{"featured": &filter.Featured, "stable": &filter.Stable} {
+		if value := query.Get(name); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return filter, errors.New("invalid " + name)
+			}
+			*target = &b
+		}
+	}
+	return filter, nil
+}
+
+// parsePage parses the page (1-based) and the page size from the query parameters.
+func parsePage(r *http.Request) (int, int, error) {
+	query := r.URL.Query()
+	page, perPage := 1, defaultPerPage
+	if value := query.Get("page"); value != "" {
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 1 {
+			return 0, 0, errors.New("invalid page")
+		}
+		page = p
+	}
+	if value := query.Get("per_page"); value != "" {
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 1 || p > maxPerPage {
+			return 0, 0, errors.New("invalid per_page, must be between 1 and " + strconv.Itoa(maxPerPage))
+		}
+		perPage = p
+	}
+	return page, perPage, nil
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// testNetworks are the networks of the test registry, Ethereum has case-insensitive addresses.
+var testNetworks = []models.Network{
+	{Id: 2, ChainId: 1, NetworkType: models.NetworkType_NETWORK_TYPE_ETH_LIKE, Name: "Ethereum", Symbol: "ETH", Decimals: 18,
+		IsActive: true, AddressRegex: "^0x[a-fA-F0-9]{40}$"},
+	{Id: 4, ChainId: 101, NetworkType: models.NetworkType_NETWORK_TYPE_SOL, Name: "Solana", Symbol: "SOL", Decimals: 9,
+		IsActive: true, AddressRegex: "^[1-9A-HJ-NP-Za-km-z]{32,44}$"},
+}
+
+// testTokens are the tokens of the test registry.
+var testTokens = []models.Token{
+	{Uuid: "usdt", Symbol: "USDT", Name: "Tether USD", IsStableToken: true, IsFeatured: true, OrderIndex: 1,
+		Tags: []string{"stablecoin"}, Addresses: []models.TokenAddress{
+			{NetworkId: 2, Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7", TokenUid: "usdt", TokenType: "ERC20", Decimals: 6},
+			{NetworkId: 4, Address: "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB", TokenUid: "usdt", TokenType: "SPL", Decimals: 6},
+		}},
+	{Uuid: "usdc", Symbol: "USDC", Name: "USD Coin", IsStableToken: true, OrderIndex: 2,
+		Tags: []string{"stablecoin"}, Addresses: []models.TokenAddress{
+			{NetworkId: 2, Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", TokenUid: "usdc", TokenType: "ERC20", Decimals: 6},
+		}},
+	{Uuid: "uni", Symbol: "UNI", Name: "Uniswap", OrderIndex: 3,
+		Tags: []string{"defi", "governance"}, Addresses: []models.TokenAddress{
+			{NetworkId: 2, Address: "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984", TokenUid: "uni", TokenType: "ERC20", Decimals: 18},
+		}},
+}
+
+// writeJSONFile writes the value as JSON into the path relative to the directory.
+func writeJSONFile(t *testing.T, dir, rel string, v any) {
+	t.Helper()
+	bytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeRegistry writes the networks, the tags and the tokens of the test registry into the directory.
+func writeRegistry(t *testing.T, dir string, tokens []models.Token) {
+	t.Helper()
+	for _, network := range testNetworks {
+		networkDir := filepath.Join(dir, "networks", strconv.FormatInt(network.Id, 10))
+		writeJSONFile(t, networkDir, "meta.json", network)
+		if err := os.WriteFile(filepath.Join(networkDir, "icon.png"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeJSONFile(t, dir, "tags/tags.json", models.Tags{Tags: []models.Tag{
+		{Id: "defi", Name: "DeFi", Description: "Decentralized finance.", Category: models.TagCategory_SECTOR},
+		{Id: "governance", Name: "Governance", Description: "Voting power.", Category: models.TagCategory_UTILITY},
+		{Id: "stablecoin", Name: "Stablecoin", Description: "Pegged to a fiat currency.", Category: models.TagCategory_ASSET},
+	}})
+	for _, token := range tokens {
+		writeJSONFile(t, dir, "tokens/"+token.Uuid+"/meta.json", token)
+		// the icons and the logos are not needed to serve the registry, only to validate it.
+		if err := os.WriteFile(filepath.Join(dir, "tokens", token.Uuid, "logo.png"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// newTestServer loads the test registry from a temporary working directory.
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+	dir := t.TempDir()
+	writeRegistry(t, dir, testTokens)
+	t.Chdir(dir)
+	tm, err := load(context.Background())
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return newServer(logger, tm)
+}
+
+// get serves the GET request with the headers.
+func get(s *server, target string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, req)
+	return rec
+}
+
+// decode decodes the JSON body of the response, failing the test unless the status is 200.
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// uids returns the uids of the tokens.
+func uids(tokens []models.Token) []string {
+	list := []string{}
+	for _, token := range tokens {
+		list = append(list, token.Uuid)
+	}
+	return list
+}
+
+func TestETag(t *testing.T) {
+	s := newTestServer(t)
+	rec := get(s, "/v1/tokens/usdt")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("GET /v1/tokens/usdt = %d with ETag %q, want 200 with an ETag", rec.Code, etag)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", got)
+	}
+	if again := get(s, "/v1/tokens/usdt"); again.Header().Get("ETag") != etag {
+		t.Errorf("ETag of the same response = %q, want %q", again.Header().Get("ETag"), etag)
+	}
+
+	tests := []struct {
+		ifNoneMatch string
+		want        int
+	}{
+		{etag, http.StatusNotModified},
+		{"W/" + etag, http.StatusNotModified},
+		{`"other", ` + etag, http.StatusNotModified},
+		{"*", http.StatusNotModified},
+		{`"other"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := get(s, "/v1/tokens/usdt", "If-None-Match", tt.ifNoneMatch)
+		if rec.Code != tt.want {
+			t.Errorf("If-None-Match %s status = %d, want %d", tt.ifNoneMatch, rec.Code, tt.want)
+		}
+		if rec.Code == http.StatusNotModified && rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s body = %q, want empty", tt.ifNoneMatch, rec.Body)
+		}
+		if rec.Header().Get("ETag") != etag {
+			t.Errorf("If-None-Match %s ETag = %q, want %q", tt.ifNoneMatch, rec.Header().Get("ETag"), etag)
+		}
+	}
+
+	// another response has another ETag.
+	if other := get(s, "/v1/tokens/usdc", "If-None-Match", etag); other.Code != http.StatusOK || other.Header().Get("ETag") == etag {
+		t.Errorf("GET /v1/tokens/usdc with the ETag of usdt = %d, ETag %q, want 200 with another ETag", other.Code, other.Header().Get("ETag"))
+	}
+}
+
+func TestPagination(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		query       string
+		want        []string
+		wantPage    int
+		wantPerPage int
+	}{
+		{"", []string{"usdt", "usdc", "uni"}, 1, defaultPerPage},
+		{"?per_page=2", []string{"usdt", "usdc"}, 1, 2},
+		{"?per_page=2&page=2", []string{"uni"}, 2, 2},
+		{"?per_page=2&page=3", []string{}, 3, 2},
+		{"?per_page=1&page=1000000", []string{}, 1000000, 1},
+		{"?per_page=500", []string{"usdt", "usdc", "uni"}, 1, maxPerPage},
+	}
+	for _, tt := range tests {
+		list := decode[tokenList](t, get(s, "/v1/tokens"+tt.query))
+		if got := uids(list.Tokens); !slices.Equal(got, tt.want) {
+			t.Errorf("GET /v1/tokens%s = %v, want %v", tt.query, got, tt.want)
+		}
+		if list.Page != tt.wantPage || list.PerPage != tt.wantPerPage || list.Total != 3 {
+			t.Errorf("GET /v1/tokens%s page = %d, per_page = %d, total = %d, want %d, %d, 3",
+				tt.query, list.Page, list.PerPage, list.Total, tt.wantPage, tt.wantPerPage)
+		}
+	}
+}
+
+func TestGetTokenByAddress(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		target string
+		want   int
+		uid    string
+	}{
+		{"/v1/networks/2/tokens/0xdAC17F958D2ee523a2206206994597C13D831ec7", http.StatusOK, "usdt"},
+		{"/v1/networks/2/tokens/0xdac17f958d2ee523a2206206994597c13d831ec7", http.StatusOK, "usdt"},
+		{"/v1/networks/2/tokens/0xDAC17F958D2EE523A2206206994597C13D831EC7", http.StatusOK, "usdt"},
+		{"/v1/networks/4/tokens/Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB", http.StatusOK, "usdt"},
+		// the Solana addresses are case-sensitive.
+		{"/v1/networks/4/tokens/es9vmfrzacermjfrf4h2fyd4kconky11mcce8benwnyb", http.StatusNotFound, ""},
+		// the address is on another network.
+		{"/v1/networks/4/tokens/0xdAC17F958D2ee523a2206206994597C13D831ec7", http.StatusNotFound, ""},
+		{"/v1/networks/99/tokens/0xdAC17F958D2ee523a2206206994597C13D831ec7", http.StatusNotFound, ""},
+		{"/v1/networks/eth/tokens/0xdAC17F958D2ee523a2206206994597C13D831ec7", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		rec := get(s, tt.target)
+		if rec.Code != tt.want {
+			t.Errorf("GET %s status = %d, want %d", tt.target, rec.Code, tt.want)
+			continue
+		}
+		if tt.uid != "" {
+			if token := decode[models.Token](t, rec); token.Uuid != tt.uid {
+				t.Errorf("GET %s = %s, want %s", tt.target, token.Uuid, tt.uid)
+			}
+		}
+	}
+}
+
+func TestFilters(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"/v1/tokens?network=4", []string{"usdt"}},
+		{"/v1/tokens?network=2", []string{"usdt", "usdc", "uni"}},
+		{"/v1/tokens?network=5", []string{}},
+		{"/v1/tokens?tag=stablecoin", []string{"usdt", "usdc"}},
+		{"/v1/tokens?tag=governance", []string{"uni"}},
+		{"/v1/tokens?featured=true", []string{"usdt"}},
+		{"/v1/tokens?featured=false", []string{"usdc", "uni"}},
+		{"/v1/tokens?stable=false", []string{"uni"}},
+		{"/v1/tokens?stable=1&featured=0", []string{"usdc"}},
+		{"/v1/tokens?q=us", []string{"usdt", "usdc"}},
+		{"/v1/tokens?q=uniswap", []string{"uni"}},
+		{"/v1/tokens?q=usd&network=4", []string{"usdt"}},
+		// the search ranks the exact symbol above the fuzzy matches.
+		{"/v1/search?q=usdc", []string{"usdc", "usdt"}},
+		{"/v1/search?q=usdc&featured=true", []string{"usdt"}},
+		{"/v1/search?q=USDC&stable=false", []string{}},
+	}
+	for _, tt := range tests {
+		list := decode[tokenList](t, get(s, tt.target))
+		if got := uids(list.Tokens); !slices.Equal(got, tt.want) {
+			t.Errorf("GET %s = %v, want %v", tt.target, got, tt.want)
+		}
+		if list.Total != len(tt.want) {
+			t.Errorf("GET %s total = %d, want %d", tt.target, list.Total, len(tt.want))
+		}
+	}
+}
+
+func TestBadRequest(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/v1/tokens?network=eth", "invalid network"},
+		{"/v1/tokens?network=0", "invalid network"},
+		{"/v1/tokens?network=-2", "invalid network"},
+		{"/v1/tokens?featured=yes", "invalid featured"},
+		{"/v1/tokens?stable=2", "invalid stable"},
+		{"/v1/tokens?page=0", "invalid page"},
+		{"/v1/tokens?page=x", "invalid page"},
+		{"/v1/tokens?per_page=0", "invalid per_page, must be between 1 and 500"},
+		{"/v1/tokens?per_page=501", "invalid per_page, must be between 1 and 500"},
+		{"/v1/search", "q is required"},
+		{"/v1/search?q=usdt&page=-1", "invalid page"},
+		{"/v1/networks/x", "invalid network id"},
+	}
+	for _, tt := range tests {
+		rec := get(s, tt.target)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want 400", tt.target, rec.Code)
+			continue
+		}
+		var response errorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil || response.Error != tt.want {
+			t.Errorf("GET %s error = %q, %v, want %q", tt.target, response.Error, err, tt.want)
+		}
+	}
+
+	for _, target := range []string{"/v1/tokens/unknown", "/v1/networks/99"} {
+		if rec := get(s, target); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s status = %d, want 404", target, rec.Code)
+		}
+	}
+}
+
+func TestNetworks(t *testing.T) {
+	s := newTestServer(t)
+	networks := decode[[]models.Network](t, get(s, "/v1/networks"))
+	if len(networks) != 2 || networks[0].Id != 2 || networks[1].Id != 4 {
+		t.Errorf("GET /v1/networks = %v, want the networks 2 and 4", networks)
+	}
+	if network := decode[models.Network](t, get(s, "/v1/networks/4")); network.Name != "Solana" {
+		t.Errorf("GET /v1/networks/4 = %s, want Solana", network.Name)
+	}
+}
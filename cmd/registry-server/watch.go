@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// watch polls the directories every interval and calls reload when their content changed,
+// a failed reload keeps the previous state and is retried on the next change.
+func (s *server) watch(ctx context.Context, interval time.Duration, reload func(ctx context.Context) error, dirs ...string) {
+	last, err := fingerprint(dirs...)
+	if err != nil {
+		s.logger.Errorf("failed to fingerprint %v: %v", dirs, err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		current, err := fingerprint(dirs...)
+		if err != nil {
+			s.logger.Errorf("failed to fingerprint %v: %v", dirs, err)
+			continue
+		}
+		if current == last {
+			continue
+		}
+		last = current
+		if err := reload(ctx); err != nil {
+			s.logger.Errorf("failed to reload the registry, serving the previous state: %v", err)
+			continue
+		}
+		s.logger.Infof("reloaded the registry")
+	}
+}
+
+// fingerprint returns a hash of the paths, sizes and modification times of the files in the directories.
+func fingerprint(dirs ...string) (string, error) {
+	h := sha256.New()
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(h, "%s\x00%d\x00%d\n", path, info.Size(), info.ModTime().UnixNano())
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+func TestWatchReload(t *testing.T) {
+	s := newTestServer(t)
+	reloads := make(chan error)
+	reload := func(ctx context.Context) error {
+		err := s.reload(ctx)
+		reloads <- err
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.watch(ctx, 10*time.Millisecond, reload, "tokens", "networks", "tags")
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	// waitReload waits for the next reload of the watcher and returns its error.
+	waitReload := func() error {
+		t.Helper()
+		select {
+		case err := <-reloads:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("the registry was not reloaded")
+			return nil
+		}
+	}
+
+	if rec := get(s, "/v1/tokens/dai"); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /v1/tokens/dai before the change = %d, want 404", rec.Code)
+	}
+	dai := models.Token{Uuid: "dai", Symbol: "DAI", Name: "Dai", IsStableToken: true, OrderIndex: 4,
+		Tags: []string{"stablecoin"}, Addresses: []models.TokenAddress{
+			{NetworkId: 2, Address: "0x6B175474E89094C44Da98b954EedeAC495271d0F", TokenUid: "dai", TokenType: "ERC20", Decimals: 18},
+		}}
+	writeRegistry(t, ".", append(testTokens, dai))
+	// the change may precede the first fingerprint of the watcher, it is touched until the first reload.
+	var err error
+	deadline := time.After(5 * time.Second)
+	for reloaded := false; !reloaded; {
+		select {
+		case err = <-reloads:
+			reloaded = true
+		case <-time.After(200 * time.Millisecond):
+			now := time.Now()
+			if err := os.Chtimes(filepath.Join("tokens", "dai", "meta.json"), now, now); err != nil {
+				t.Fatal(err)
+			}
+		case <-deadline:
+			t.Fatal("the registry was not reloaded")
+		}
+	}
+	if err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	if token := decode[models.Token](t, get(s, "/v1/networks/2/tokens/0x6b175474e89094c44da98b954eedeac495271d0f")); token.Uuid != "dai" {
+		t.Errorf("GET the added address = %s, want dai", token.Uuid)
+	}
+	// the search index is swapped with the token manager.
+	if list := decode[tokenList](t, get(s, "/v1/search?q=dai")); len(list.Tokens) == 0 || list.Tokens[0].Uuid != "dai" {
+		t.Errorf("GET /v1/search?q=dai = %v, want dai first", uids(list.Tokens))
+	}
+
+	// a broken registry keeps the previous state.
+	metaPath := filepath.Join("tokens", "dai", "meta.json")
+	if err := os.WriteFile(metaPath, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitReload(); err == nil {
+		t.Fatal("reload() of a broken meta.json error = nil, want an error")
+	}
+	if rec := get(s, "/v1/tokens/dai"); rec.Code != http.StatusOK {
+		t.Errorf("GET /v1/tokens/dai after a failed reload = %d, want the previous state", rec.Code)
+	}
+
+	// the fixed registry is reloaded on the next change.
+	if err := os.RemoveAll(filepath.Join("tokens", "dai")); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitReload(); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	if rec := get(s, "/v1/tokens/dai"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /v1/tokens/dai after the removal = %d, want 404", rec.Code)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "meta.json")
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	first, err := fingerprint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := fingerprint(dir); again != first {
+		t.Errorf("fingerprint() of an unchanged directory = %s, want %s", again, first)
+	}
+	if err := os.WriteFile(path, []byte(`{"a": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := fingerprint(dir); changed == first {
+		t.Errorf("fingerprint() of a changed file = %s, want another fingerprint", changed)
+	}
+	if _, err := fingerprint(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("fingerprint() of a missing directory error = nil, want an error")
+	}
+}
@@ -0,0 +1,313 @@
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// Changelog is the model for the changes between two registry states.
+type Changelog struct {
+	// The label of the previous registry state (e.g., "git:v1.2.0", "dist").
+	From string `json:"from"`
+
+	// The label of the current registry state.
+	To string `json:"to"`
+
+	// The tokens that were added to the registry.
+	Added []TokenRef `json:"added"`
+
+	// The tokens that were removed from the registry.
+	Removed []TokenRef `json:"removed"`
+
+	// The tokens that were disabled since the previous state.
+	Disabled []TokenRef `json:"disabled"`
+
+	// The tokens that were enabled again since the previous state.
+	Enabled []TokenRef `json:"enabled"`
+
+	// The addresses that were added to the existing tokens.
+	NewAddresses []AddressRef `json:"new_addresses"`
+
+	// The addresses that were removed from the existing tokens.
+	RemovedAddresses []AddressRef `json:"removed_addresses"`
+
+	// The changed fields per token.
+	Changed []TokenChange `json:"changed"`
+}
+
+// TokenRef is a reference to a token in the changelog.
+type TokenRef struct {
+	Uid    string `json:"uid"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// AddressRef is a reference to a token address in the changelog.
+type AddressRef struct {
+	Uid       string `json:"uid"`
+	Symbol    string `json:"symbol"`
+	NetworkId int32  `json:"network_id"`
+	Address   string `json:"address"`
+}
+
+// TokenChange is the list of the changed fields of a token.
+type TokenChange struct {
+	TokenRef
+	Fields []FieldChange `json:"fields"`
+}
+
+// FieldChange is a changed field of a token.
+// the field is the json name of the field, the address fields are prefixed by
+// addresses[:network_id/:address] (e.g., "addresses[2/0xdac1...].decimals").
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old"`
+	New   json.RawMessage `json:"new"`
+}
+
+// IsEmpty reports whether there is no change.
+func (c *Changelog) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Disabled) == 0 && len(c.Enabled) == 0 &&
+		len(c.NewAddresses) == 0 && len(c.RemovedAddresses) == 0 && len(c.Changed) == 0
+}
+
+// Diff compares the previous tokens with the current tokens.
+// the tokens are matched by their uid, the addresses by their network id and address.
+func Diff(previous, current []models.Token) (*Changelog, error) {
+	c := &Changelog{
+		Added:            []TokenRef{},
+		Removed:          []TokenRef{},
+		Disabled:         []TokenRef{},
+		Enabled:          []TokenRef{},
+		NewAddresses:     []AddressRef{},
+		RemovedAddresses: []AddressRef{},
+		Changed:          []TokenChange{},
+	}
+	previousByUid := indexTokens(previous)
+	currentByUid := indexTokens(current)
+
+	for _, uid := range sortedKeys(currentByUid) {
+		token := currentByUid[uid]
+		old, ok := previousByUid[uid]
+		if !ok {
+			c.Added = append(c.Added, newTokenRef(uid, token))
+			continue
+		}
+		if token.IsDisabled && !old.IsDisabled {
+			c.Disabled = append(c.Disabled, newTokenRef(uid, token))
+		}
+		if !token.IsDisabled && old.IsDisabled {
+			c.Enabled = append(c.Enabled, newTokenRef(uid, token))
+		}
+		fields, err := diffToken(old, token)
+		if err != nil {
+			return nil, fmt.Errorf("token %s: %v", uid, err)
+		}
+		if len(fields) > 0 {
+			c.Changed = append(c.Changed, TokenChange{TokenRef: newTokenRef(uid, token), Fields: fields})
+		}
+
+		oldAddresses := indexAddresses(old)
+		newAddresses := indexAddresses(token)
+		for _, key := range sortedKeys(newAddresses) {
+			if _, ok := oldAddresses[key]; !ok {
+				c.NewAddresses = append(c.NewAddresses, newAddressRef(uid, token, newAddresses[key]))
+			}
+		}
+		for _, key := range sortedKeys(oldAddresses) {
+			if _, ok := newAddresses[key]; !ok {
+				c.RemovedAddresses = append(c.RemovedAddresses, newAddressRef(uid, token, oldAddresses[key]))
+			}
+		}
+	}
+	for _, uid := range sortedKeys(previousByUid) {
+		if _, ok := currentByUid[uid]; !ok {
+			c.Removed = append(c.Removed, newTokenRef(uid, previousByUid[uid]))
+		}
+	}
+	return c, nil
+}
+
+// diffToken returns the changed fields between the two versions of a token.
+func diffToken(old, token *models.Token) ([]FieldChange, error) {
+	fields, err := diffFields("", old, token, "addresses")
+	if err != nil {
+		return nil, err
+	}
+	oldAddresses := indexAddresses(old)
+	newAddresses := indexAddresses(token)
+	for _, key := range sortedKeys(newAddresses) {
+		oldAddress, ok := oldAddresses[key]
+		if !ok {
+			continue
+		}
+		addressFields, err := diffFields(fmt.Sprintf("addresses[%s].", key), oldAddress, newAddresses[key])
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, addressFields...)
+	}
+	return fields, nil
+}
+
+// diffFields compares the json fields of the two values, skipping the given fields.
+func diffFields(prefix string, old, new any, skip ...string) ([]FieldChange, error) {
+	oldFields, err := jsonFields(old)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := jsonFields(new)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]struct{})
+	for key := range oldFields {
+		keys[key] = struct{}{}
+	}
+	for key := range newFields {
+		keys[key] = struct{}{}
+	}
+	for _, key := range skip {
+		delete(keys, key)
+	}
+	var changes []FieldChange
+	for _, key := range sortedKeys(keys) {
+		oldValue, newValue := oldFields[key], newFields[key]
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: prefix + key, Old: nullIfEmpty(oldValue), New: nullIfEmpty(newValue)})
+	}
+	return changes, nil
+}
+
+// jsonFields returns the compact json encoding of every field of the value.
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(raw, &fields)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, value); err != nil {
+			return nil, err
+		}
+		fields[key] = buf.Bytes()
+	}
+	return fields, nil
+}
+
+func nullIfEmpty(value json.RawMessage) json.RawMessage {
+	if len(value) == 0 {
+		return json.RawMessage("null")
+	}
+	return value
+}
+
+// indexTokens maps the tokens by their uid.
+func indexTokens(tokens []models.Token) map[string]*models.Token {
+	byUid := make(map[string]*models.Token, len(tokens))
+	for i := range tokens {
+		byUid[tokens[i].Uuid] = &tokens[i]
+	}
+	return byUid
+}
+
+// indexAddresses maps the token addresses by ":network_id/:address".
+func indexAddresses(token *models.Token) map[string]*models.TokenAddress {
+	byKey := make(map[string]*models.TokenAddress, len(token.Addresses))
+	for i := range token.Addresses {
+		address := &token.Addresses[i]
+		byKey[fmt.Sprintf("%d/%s", address.NetworkId, address.Address)] = address
+	}
+	return byKey
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newTokenRef(uid string, token *models.Token) TokenRef {
+	return TokenRef{Uid: uid, Symbol: token.Symbol, Name: token.Name}
+}
+
+func newAddressRef(uid string, token *models.Token, address *models.TokenAddress) AddressRef {
+	return AddressRef{Uid: uid, Symbol: token.Symbol, NetworkId: address.NetworkId, Address: address.Address}
+}
+
+// Markdown renders the changelog as a markdown document.
+func (c *Changelog) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Registry changelog\n\n")
+	if c.From != "" || c.To != "" {
+		fmt.Fprintf(&b, "Changes from `%s` to `%s`.\n\n", c.From, c.To)
+	}
+	if c.IsEmpty() {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+	writeTokens := func(title string, refs []TokenRef) {
+		if len(refs) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "## %s (%d)\n\n", title, len(refs))
+		for _, ref := range refs {
+			fmt.Fprintf(&b, "- **%s** %s (`%s`)\n", ref.Symbol, ref.Name, ref.Uid)
+		}
+		b.WriteString("\n")
+	}
+	writeAddresses := func(title string, refs []AddressRef) {
+		if len(refs) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "## %s (%d)\n\n", title, len(refs))
+		for _, ref := range refs {
+			fmt.Fprintf(&b, "- **%s** on network %d: `%s`\n", ref.Symbol, ref.NetworkId, ref.Address)
+		}
+		b.WriteString("\n")
+	}
+	writeTokens("Added tokens", c.Added)
+	writeTokens("Removed tokens", c.Removed)
+	writeTokens("Disabled tokens", c.Disabled)
+	writeTokens("Enabled tokens", c.Enabled)
+	writeAddresses("New addresses", c.NewAddresses)
+	writeAddresses("Removed addresses", c.RemovedAddresses)
+	if len(c.Changed) > 0 {
+		fmt.Fprintf(&b, "## Changed tokens (%d)\n\n", len(c.Changed))
+		for _, change := range c.Changed {
+			fmt.Fprintf(&b, "### %s (`%s`)\n\n", change.Symbol, change.Uid)
+			b.WriteString("| Field | Old | New |\n|---|---|---|\n")
+			for _, field := range change.Fields {
+				fmt.Fprintf(&b, "| `%s` | %s | %s |\n", field.Field, markdownCell(field.Old), markdownCell(field.New))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// markdownCell renders a json value inside a markdown table cell.
+func markdownCell(value json.RawMessage) string {
+	s := string(value)
+	const maxLen = 120
+	if runes := []rune(s); len(runes) > maxLen {
+		s = string(runes[:maxLen]) + "…"
+	}
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return "`" + strings.ReplaceAll(s, "`", "'") + "`"
+}
@@ -0,0 +1,188 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// gitPrefix is the prefix of the sources that are read from a git ref.
+const gitPrefix = "git:"
+
+// blocklistFile is the name of the built blocklist next to the built tokens.json.
+const blocklistFile = "blocklist.json"
+
+// Load loads the tokens of a registry state, the source can be:
+// - git:<ref> (the tokens/*/meta.json files at the git ref, e.g., "git:main")
+// - a dist directory (the directory contains tokens.json)
+// - a source tree (the directory contains tokens/*/meta.json)
+// - a tokens.json file
+// the built tokens.json has no disabled or scam tokens, they are loaded from the blocklist.json
+// next to it, without it the tokens enabled since the state are reported as added.
+// the git refs and the source trees have every token.
+func Load(ctx context.Context, source string) ([]models.Token, error) {
+	if ref, ok := strings.CutPrefix(source, gitPrefix); ok {
+		return LoadGitRef(ctx, ref)
+	}
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return LoadTokensFile(source)
+	}
+	if _, err := os.Stat(filepath.Join(source, "tokens.json")); err == nil {
+		return LoadTokensFile(filepath.Join(source, "tokens.json"))
+	}
+	return LoadSourceTree(source)
+}
+
+// LoadTokensFile loads the tokens from a built tokens.json file,
+// and the disabled and scam tokens from the blocklist.json next to it, if any.
+func LoadTokensFile(file string) ([]models.Token, error) {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []models.Token
+	err = json.Unmarshal(bytes, &tokens)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", file, err)
+	}
+	blocked, err := LoadBlocklistFile(filepath.Join(filepath.Dir(file), blocklistFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	listed := make(map[string]struct{}, len(tokens))
+	for _, token := range tokens {
+		listed[token.Uuid] = struct{}{}
+	}
+	for _, token := range blocked {
+		if _, ok := listed[token.Uuid]; !ok {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+// LoadBlocklistFile loads the disabled and scam tokens from a built blocklist.json file,
+// the tokens only have their uid, symbol, name, flags and the blocked addresses.
+func LoadBlocklistFile(file string) ([]models.Token, error) {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var blocklist models.Blocklist
+	err = json.Unmarshal(bytes, &blocklist)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", file, err)
+	}
+	networkIds := make([]int64, 0, len(blocklist.Networks))
+	for networkId := range blocklist.Networks {
+		networkIds = append(networkIds, networkId)
+	}
+	slices.Sort(networkIds)
+
+	var tokens []models.Token
+	index := make(map[string]int)
+	for _, networkId := range networkIds {
+		for _, blocked := range blocklist.Networks[networkId] {
+			i, ok := index[blocked.TokenUuid]
+			if !ok {
+				i = len(tokens)
+				index[blocked.TokenUuid] = i
+				tokens = append(tokens, models.Token{
+					Uuid:       blocked.TokenUuid,
+					Symbol:     blocked.Symbol,
+					Name:       blocked.Name,
+					IsScam:     blocked.Reason == models.BlockReason_SCAM,
+					IsDisabled: blocked.Reason == models.BlockReason_DISABLED,
+				})
+			}
+			tokens[i].Addresses = append(tokens[i].Addresses, models.TokenAddress{
+				Address:   blocked.Address,
+				TokenUid:  blocked.TokenUuid,
+				NetworkId: int32(networkId),
+				Caip19:    blocked.Caip19,
+			})
+		}
+	}
+	return tokens, nil
+}
+
+// LoadSourceTree loads the tokens from the tokens/*/meta.json files of the source tree.
+func LoadSourceTree(root string) ([]models.Token, error) {
+	entries, err := os.ReadDir(filepath.Join(root, "tokens"))
+	if err != nil {
+		return nil, err
+	}
+	var tokens []models.Token
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "_example" {
+			continue
+		}
+		bytes, err := os.ReadFile(filepath.Join(root, "tokens", entry.Name(), "meta.json"))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		token, err := parseMeta(entry.Name(), bytes)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// LoadGitRef loads the tokens from the tokens/*/meta.json files at the git ref.
+func LoadGitRef(ctx context.Context, ref string) ([]models.Token, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid git ref: %q", ref)
+	}
+	out, err := exec.CommandContext(ctx, "git", "ls-tree", "-r", "--name-only", ref, "--", "tokens").Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the tokens at %s: %v", ref, err)
+	}
+	var tokens []models.Token
+	for _, file := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Split(file, "/")
+		if len(parts) != 3 || parts[2] != "meta.json" || parts[1] == "_example" {
+			continue
+		}
+		bytes, err := exec.CommandContext(ctx, "git", "show", ref+":"+path.Clean(file)).Output()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s at %s: %v", file, ref, err)
+		}
+		token, err := parseMeta(parts[1], bytes)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// parseMeta parses the meta.json of the token folder, the uid defaults to the folder name.
+func parseMeta(tokenUid string, bytes []byte) (models.Token, error) {
+	var token models.Token
+	err := json.Unmarshal(bytes, &token)
+	if err != nil {
+		return token, fmt.Errorf("cannot parse tokens/%s/meta.json: %v", tokenUid, err)
+	}
+	if token.Uuid == "" {
+		token.Uuid = tokenUid
+	}
+	return token, nil
+}
@@ -0,0 +1,58 @@
+package changelog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDistDirectory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("tokens.json", `[{"uuid": "usdt", "symbol": "USDT", "addresses": [{"address": "0xdac1", "network_id": 2}]}]`)
+
+	tokens, err := Load(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Load() without a blocklist error = %v", err)
+	}
+	if len(tokens) != 1 {
+		t.Fatalf("Load() without a blocklist returned %d tokens, want 1", len(tokens))
+	}
+
+	write("blocklist.json", `{"networks": {
+		"2": [
+			{"address": "0xbad1", "token_uuid": "scam", "symbol": "USDT", "name": "Tether", "reason": "scam"},
+			{"address": "0xdead", "token_uuid": "old", "symbol": "OLD", "name": "Old", "reason": "disabled"}
+		],
+		"3": [{"address": "0xdead", "token_uuid": "old", "symbol": "OLD", "name": "Old", "reason": "disabled"}]
+	}}`)
+	tokens, err = Load(context.Background(), filepath.Join(dir, "tokens.json"))
+	if err != nil {
+		t.Fatalf("Load() with a blocklist error = %v", err)
+	}
+	byUid := indexTokens(tokens)
+	if len(tokens) != 3 {
+		t.Fatalf("Load() with a blocklist returned %d tokens, want 3", len(tokens))
+	}
+	if scam := byUid["scam"]; !scam.IsScam || scam.IsDisabled {
+		t.Errorf("scam token flags = is_scam %v, is_disabled %v, want true, false", scam.IsScam, scam.IsDisabled)
+	}
+	old := byUid["old"]
+	if !old.IsDisabled || old.IsScam {
+		t.Errorf("disabled token flags = is_scam %v, is_disabled %v, want false, true", old.IsScam, old.IsDisabled)
+	}
+	if len(old.Addresses) != 2 || old.Addresses[0].NetworkId != 2 || old.Addresses[1].NetworkId != 3 {
+		t.Errorf("disabled token addresses = %+v, want the addresses on the networks 2 and 3", old.Addresses)
+	}
+
+	write("blocklist.json", `{"networks": [`)
+	if _, err := Load(context.Background(), dir); err == nil {
+		t.Errorf("Load() with an invalid blocklist succeeded")
+	}
+}
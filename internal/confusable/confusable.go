@@ -0,0 +1,99 @@
+package confusable
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Skeleton returns the skeleton of the string, two strings that look the same
+// have the same skeleton (e.g., "USDC", "usdc", "USDС" with a Cyrillic С, "USDⅭ" with a Roman numeral).
+// the skeleton is built in the spirit of the Unicode TR39 skeleton:
+// - the string is decomposed by NFKD, the compatibility decomposition of NFKC, which also splits off the diacritics
+// (e.g., fullwidth forms, Roman numerals, mathematical alphanumerics, ligatures, super and subscripts, circled letters).
+// - the default ignorable characters and the combining marks are removed.
+// - the confusable characters are mapped to their prototype (a subset of the TR39 confusables).
+// - the case is folded, and the folded letters are mapped to their prototype again.
+// the skeleton is only meant for the comparison, it is not a display string.
+func Skeleton(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if isIgnorable(r) {
+			continue
+		}
+		if prototype, ok := prototypes[r]; ok {
+			b.WriteString(prototype)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	folded := strings.ToLower(b.String())
+	// the lowercase letters may have their own confusables (e.g., "m" and "rn").
+	var out strings.Builder
+	for _, r := range folded {
+		if prototype, ok := prototypes[r]; ok {
+			out.WriteString(strings.ToLower(prototype))
+			continue
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// Equal reports whether two strings look the same, refer to Skeleton.
+func Equal(a, b string) bool {
+	return Skeleton(a) == Skeleton(b)
+}
+
+// isIgnorable reports whether the rune is invisible or only decorates the previous rune.
+func isIgnorable(r rune) bool {
+	switch {
+	case unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r):
+		// combining marks (e.g., "U" followed by U+0308 looks like "Ü", which looks like "U").
+		return true
+	case r == 0x00AD, r == 0x034F, r == 0x061C, r == 0x180E:
+		return true
+	case r >= 0x200B && r <= 0x200F, r >= 0x202A && r <= 0x202E, r >= 0x2060 && r <= 0x206F:
+		// zero width characters, bidi controls and invisible operators.
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F, r == 0xFEFF:
+		// variation selectors and the byte order mark.
+		return true
+	}
+	return false
+}
+
+// prototypes map the confusable characters to the character they look like,
+// the uppercase and lowercase forms of a letter map to the same prototype (e.g., "I" and "i" to "l"),
+// so that the skeleton does not depend on the case.
+var prototypes = map[rune]string{
+	// digits and punctuation that look like letters.
+	'0': "O", '1': "l", '|': "l", 'I': "l", 'i': "l", 0x0131: "l", 0x01C0: "l",
+	'm': "rn",
+	// Latin letters with a stroke, which NFKD does not decompose.
+	0x00D8: "O", 0x00F8: "o", 0x0110: "D", 0x0111: "d", 0x0126: "H", 0x0127: "h",
+	0x0141: "L", 0x0142: "l", 0x0166: "T", 0x0167: "t", 0x0180: "b", 0x01B5: "Z", 0x01B6: "z",
+	// Cyrillic.
+	0x0410: "A", 0x0412: "B", 0x0415: "E", 0x041A: "K", 0x041C: "M", 0x041D: "H", 0x041E: "O",
+	0x0420: "P", 0x0421: "C", 0x0422: "T", 0x0423: "Y", 0x0425: "X", 0x0405: "S", 0x0406: "l",
+	0x0408: "J", 0x04AE: "Y", 0x04C0: "l", 0x0417: "3", 0x0411: "6", 0x0427: "4",
+	0x0430: "a", 0x0435: "e", 0x043E: "o", 0x0440: "p", 0x0441: "c", 0x0443: "y", 0x0445: "x",
+	0x0455: "s", 0x0456: "i", 0x0458: "j", 0x04BB: "h", 0x0501: "d", 0x051B: "q", 0x051D: "w",
+	0x04CF: "l", 0x0457: "i", 0x0432: "B", 0x043A: "K", 0x043C: "M", 0x043D: "H", 0x0442: "T",
+	// Greek.
+	0x0391: "A", 0x0392: "B", 0x0395: "E", 0x0396: "Z", 0x0397: "H", 0x0399: "l", 0x039A: "K",
+	0x039C: "M", 0x039D: "N", 0x039F: "O", 0x03A1: "P", 0x03A4: "T", 0x03A5: "Y", 0x03A7: "X",
+	0x03B1: "a", 0x03B9: "i", 0x03BA: "K", 0x03BD: "v", 0x03BF: "o", 0x03C1: "p", 0x03C5: "u",
+	0x03C7: "X", 0x03F2: "c", 0x03F3: "j",
+	// Armenian, Cherokee and Latin letters that look like other Latin letters.
+	0x0555: "O", 0x0585: "o", 0x054D: "U", 0x057D: "u", 0x13A0: "D", 0x13A1: "R", 0x13A2: "T",
+	0x13A9: "Y", 0x13AA: "A", 0x13AB: "J", 0x13AC: "E", 0x13B3: "W", 0x13B7: "M", 0x13BB: "H",
+	0x13C0: "G", 0x13C3: "Z", 0x13CF: "b", 0x13D2: "R", 0x13DA: "S", 0x13DE: "L", 0x13DF: "C",
+	0x0261: "g", 0x0251: "a", 0x026A: "l", 0x1D00: "A", 0x0299: "B", 0x1D04: "C", 0x1D05: "D",
+	0x1D07: "E", 0x0262: "G", 0x029C: "H", 0x1D0A: "J", 0x1D0B: "K", 0x029F: "L", 0x1D0D: "M",
+	0x0274: "N", 0x1D0F: "O", 0x1D18: "P", 0x0280: "R", 0x1D1B: "T", 0x1D1C: "U", 0x1D20: "V",
+	0x1D21: "W", 0x028F: "Y", 0x1D22: "Z",
+	// currency and letterlike symbols.
+	0x20AC: "E", 0x0192: "f", 0x00A2: "c", 0x2C60: "L",
+}
@@ -0,0 +1,71 @@
+package confusable
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		// case.
+		{"USDC", "usdc", true},
+		{"LINK", "link", true},
+		{"UNI", "uni", true},
+		{"Uniswap", "UNISWAP", true},
+		{"Chainlink", "chainLINK", true},
+		// digits and punctuation.
+		{"L1NK", "link", true},
+		{"l|nk", "LINK", true},
+		{"B0NK", "bonk", true},
+		{"rnana", "MANA", true},
+		// Cyrillic and Greek.
+		{"USDС", "USDC", true},
+		{"АΑVЕ", "aave", true},
+		{"ЅНІВ", "shib", true},
+		{"ТΗΕТΑ", "theta", true},
+		{"dаі", "DAI", true},
+		// compatibility characters.
+		{"USDⅭ", "USDC", true},
+		{"ＵＳＤＴ", "usdt", true},
+		{"𝐔𝐍𝐈", "uni", true},
+		{"Ⓤⓝⓘ", "UNI", true},
+		{"ﬁl", "FIL", true},
+		{"ETH²", "ETH2", true},
+		// diacritics, strokes and invisible characters.
+		{"Ünı", "UNI", true},
+		{"ÜNI", "uni", true},
+		{"ØP", "op", true},
+		{"Łink", "link", true},
+		{"US\u200bDT", "USDT", true},
+		{"US\u00adDT", "usdt", true},
+		// different strings.
+		{"USDC", "USDT", false},
+		{"LINK", "LINA", false},
+		{"UNI", "ONE", false},
+		{"ETH", "ETC", false},
+		{"", "USDT", false},
+	}
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.want {
+			t.Errorf("Equal(%q, %q) = %v, want %v (skeletons %q and %q)", tt.a, tt.b, got, tt.want, Skeleton(tt.a), Skeleton(tt.b))
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"", ""},
+		{"link", "llnk"},
+		{"LINK", "llnk"},
+		{"MANA", "rnana"},
+		{"Ⅻ", "xll"},
+		{"™", "trn"},
+	}
+	for _, tt := range tests {
+		if got := Skeleton(tt.s); got != tt.want {
+			t.Errorf("Skeleton(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
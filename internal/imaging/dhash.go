@@ -0,0 +1,78 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"math/bits"
+)
+
+// hashSize is the width and height of the difference hash grid,
+// the hash has hashSize*hashSize bits.
+const hashSize = 16
+
+// Hash is a perceptual difference hash (dHash) of an image.
+// the similar images have hashes with a small distance, refer to Distance.
+type Hash [hashSize * hashSize / 64]uint64
+
+// DifferenceHash returns the difference hash of the image.
+// the image is composited over white, downscaled to 17x16 and converted to grayscale,
+// every bit tells whether a pixel is brighter than its right neighbour.
+// a 64-bit hash cannot tell the round logos apart, so the grid is 16x16 (256 bits).
+func DifferenceHash(img image.Image) Hash {
+	small := Resize(img, hashSize+1, hashSize)
+	var hash Hash
+	for y := 0; y < hashSize; y++ {
+		row := small.Pix[y*small.Stride:]
+		for x := 0; x < hashSize; x++ {
+			if luma(row[x*4:]) > luma(row[(x+1)*4:]) {
+				bit := y*hashSize + x
+				hash[bit/64] |= 1 << (63 - bit%64)
+			}
+		}
+	}
+	return hash
+}
+
+// Distance returns the hamming distance of two hashes, the number of the different bits.
+func (h Hash) Distance(other Hash) int {
+	distance := 0
+	for i := range h {
+		distance += bits.OnesCount64(h[i] ^ other[i])
+	}
+	return distance
+}
+
+// String returns the hex encoding of the hash.
+func (h Hash) String() string {
+	var b [len(h) * 8]byte
+	for i, v := range h {
+		binary.BigEndian.PutUint64(b[i*8:], v)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ParseHash parses the hex encoding of a hash.
+// it returns an error if any.
+func ParseHash(s string) (Hash, error) {
+	var hash Hash
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return hash, err
+	}
+	if len(b) != len(hash)*8 {
+		return hash, fmt.Errorf("hash must be %d hex characters, got: %d", len(hash)*16, len(s))
+	}
+	for i := range hash {
+		hash[i] = binary.BigEndian.Uint64(b[i*8:])
+	}
+	return hash, nil
+}
+
+// luma returns the brightness of a premultiplied RGBA pixel composited over white.
+func luma(p []uint8) int {
+	background := 255 - int(p[3])
+	r, g, b := int(p[0])+background, int(p[1])+background, int(p[2])+background
+	return 299*r + 587*g + 114*b
+}
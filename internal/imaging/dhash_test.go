@@ -0,0 +1,89 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// horizontalGradient returns an opaque gray gradient, brightening to the right if rising.
+func horizontalGradient(size int, rising bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			v := uint8(x * 255 / (size - 1))
+			if !rising {
+				v = 255 - v
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 0xff})
+		}
+	}
+	return img
+}
+
+func TestDifferenceHash(t *testing.T) {
+	var zero, full Hash
+	for i := range full {
+		full[i] = ^uint64(0)
+	}
+	tests := []struct {
+		name string
+		img  image.Image
+		want Hash
+	}{
+		// no pixel is brighter than its right neighbour.
+		{"rising gradient", horizontalGradient(68, true), zero},
+		// every pixel is brighter than its right neighbour.
+		{"falling gradient", horizontalGradient(68, false), full},
+		// a transparent image is composited over white, every pixel is the same.
+		{"transparent", image.NewNRGBA(image.Rect(0, 0, 32, 32)), zero},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DifferenceHash(tt.img); got != tt.want {
+				t.Errorf("DifferenceHash() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// the hash does not depend on the image size.
+	if a, b := DifferenceHash(horizontalGradient(64, false)), DifferenceHash(horizontalGradient(256, false)); a.Distance(b) != 0 {
+		t.Errorf("the hashes of the same image at 64 and 256 px differ by %d bits", a.Distance(b))
+	}
+}
+
+func TestDistance(t *testing.T) {
+	var a, b Hash
+	if got := a.Distance(b); got != 0 {
+		t.Errorf("Distance() of equal hashes = %d, want 0", got)
+	}
+	b[0] = 0b1011
+	b[3] = 1 << 63
+	if got := a.Distance(b); got != 4 {
+		t.Errorf("Distance() = %d, want 4", got)
+	}
+	for i := range b {
+		a[i], b[i] = 0, ^uint64(0)
+	}
+	if got := a.Distance(b); got != hashSize*hashSize {
+		t.Errorf("Distance() of the complement = %d, want %d", got, hashSize*hashSize)
+	}
+}
+
+func TestParseHash(t *testing.T) {
+	hash := Hash{0x0123456789abcdef, 0, ^uint64(0), 42}
+	s := hash.String()
+	if len(s) != 64 || !strings.HasPrefix(s, "0123456789abcdef0000000000000000ffffffffffffffff") {
+		t.Fatalf("String() = %s, want the big endian hex of the words", s)
+	}
+	parsed, err := ParseHash(s)
+	if err != nil || parsed != hash {
+		t.Errorf("ParseHash(String()) = %s, %v, want %s", parsed, err, hash)
+	}
+	for _, invalid := range []string{"", "xyz", s[:62], s + "00"} {
+		if _, err := ParseHash(invalid); err == nil {
+			t.Errorf("ParseHash(%q) error = nil, want an error", invalid)
+		}
+	}
+}
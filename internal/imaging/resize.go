@@ -0,0 +1,111 @@
+package imaging
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Resize resizes the image to width x height.
+// the resampling only uses integer arithmetic, so the output is the same on every platform:
+// - downscaling averages the covered source area (box filter).
+// - upscaling interpolates between the source pixels (bilinear filter).
+// the colors are resampled with premultiplied alpha, so the transparent pixels do not bleed.
+func Resize(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	// image.RGBA is premultiplied, draw.Draw converts any color model into it.
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	xWeights, xTotal := resampleWeights(bounds.Dx(), width)
+	yWeights, yTotal := resampleWeights(bounds.Dy(), height)
+
+	// horizontal pass, the values are scaled by xTotal.
+	tmp := make([]int64, width*bounds.Dy()*4)
+	for y := 0; y < bounds.Dy(); y++ {
+		row := rgba.Pix[y*rgba.Stride:]
+		for x := 0; x < width; x++ {
+			var acc [4]int64
+			for _, w := range xWeights[x] {
+				p := row[w.index*4:]
+				for c := 0; c < 4; c++ {
+					acc[c] += int64(p[c]) * w.weight
+				}
+			}
+			copy(tmp[(y*width+x)*4:], acc[:])
+		}
+	}
+
+	// vertical pass, the values are scaled by xTotal * yTotal.
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	total := xTotal * yTotal
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var acc [4]int64
+			for _, w := range yWeights[y] {
+				p := tmp[(w.index*width+x)*4:]
+				for c := 0; c < 4; c++ {
+					acc[c] += p[c] * w.weight
+				}
+			}
+			d := dst.Pix[y*dst.Stride+x*4:]
+			for c := 0; c < 4; c++ {
+				d[c] = uint8((acc[c] + total/2) / total)
+			}
+			// rounding may leave a color channel above the alpha of a premultiplied pixel.
+			for c := 0; c < 3; c++ {
+				if d[c] > d[3] {
+					d[c] = d[3]
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// sourceWeight is the weight of a source pixel in a destination pixel.
+type sourceWeight struct {
+	index  int
+	weight int64
+}
+
+// resampleWeights returns the source pixels and their integer weights for every destination pixel,
+// and the total weight every destination pixel sums up to.
+func resampleWeights(srcSize, dstSize int) ([][]sourceWeight, int64) {
+	weights := make([][]sourceWeight, dstSize)
+	src, dst := int64(srcSize), int64(dstSize)
+	if dst <= src {
+		// box filter: in units of 1/dst, the destination pixel i covers [i*src, (i+1)*src)
+		// and the source pixel j covers [j*dst, (j+1)*dst).
+		for i := int64(0); i < dst; i++ {
+			start, end := i*src, (i+1)*src
+			for j := start / dst; j*dst < end; j++ {
+				overlap := min(end, (j+1)*dst) - max(start, j*dst)
+				if overlap > 0 {
+					weights[i] = append(weights[i], sourceWeight{index: int(j), weight: overlap})
+				}
+			}
+		}
+		return weights, src
+	}
+	// bilinear filter: in units of 1/(2*dst), the center of the destination pixel i
+	// maps to the source position ((2i+1)*src - dst) relative to the first source pixel center.
+	unit := 2 * dst
+	for i := int64(0); i < dst; i++ {
+		pos := (2*i+1)*src - dst
+		if pos <= 0 {
+			weights[i] = []sourceWeight{{index: 0, weight: unit}}
+			continue
+		}
+		left := pos / unit
+		frac := pos % unit
+		if left >= src-1 {
+			weights[i] = []sourceWeight{{index: int(src - 1), weight: unit}}
+			continue
+		}
+		weights[i] = []sourceWeight{
+			{index: int(left), weight: unit - frac},
+			{index: int(left + 1), weight: frac},
+		}
+	}
+	return weights, unit
+}
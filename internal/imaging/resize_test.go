@@ -0,0 +1,95 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// grayRow returns an opaque 1 pixel high image with the gray levels.
+func grayRow(levels ...uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, len(levels), 1))
+	for x, v := range levels {
+		img.SetNRGBA(x, 0, color.NRGBA{R: v, G: v, B: v, A: 0xff})
+	}
+	return img
+}
+
+// grayLevels returns the red channel of the first row of the opaque image.
+func grayLevels(img *image.RGBA) []uint8 {
+	levels := make([]uint8, img.Bounds().Dx())
+	for x := range levels {
+		levels[x] = img.Pix[x*4]
+	}
+	return levels
+}
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   []uint8
+		width int
+		want  []uint8
+	}{
+		{"same size", []uint8{0, 100, 200}, 3, []uint8{0, 100, 200}},
+		{"halve", []uint8{0, 100, 200, 250}, 2, []uint8{50, 225}},
+		// every destination pixel covers 1.5 source pixels.
+		{"box filter with partial coverage", []uint8{0, 90, 180}, 2, []uint8{30, 150}},
+		{"single pixel", []uint8{10, 20, 30, 40}, 1, []uint8{25}},
+		// the centers of the outer destination pixels are outside the source centers.
+		{"bilinear upscale", []uint8{0, 200}, 4, []uint8{0, 50, 150, 200}},
+		{"bilinear upscale by 3", []uint8{0, 240}, 6, []uint8{0, 0, 80, 160, 240, 240}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Resize(grayRow(tt.src...), tt.width, 1)
+			if got.Bounds() != image.Rect(0, 0, tt.width, 1) {
+				t.Fatalf("Resize() bounds = %v, want %dx1", got.Bounds(), tt.width)
+			}
+			if levels := grayLevels(got); string(levels) != string(tt.want) {
+				t.Errorf("Resize() = %v, want %v", levels, tt.want)
+			}
+		})
+	}
+}
+
+func TestResizeSquare(t *testing.T) {
+	// a 4x4 checkerboard of 2x2 black and white blocks averages to four flat pixels.
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			v := uint8(0)
+			if (x/2+y/2)%2 == 1 {
+				v = 0xff
+			}
+			src.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 0xff})
+		}
+	}
+	got := Resize(src, 2, 2)
+	want := []uint8{0, 0xff, 0xff, 0}
+	for i, v := range want {
+		if p := got.Pix[i*4 : i*4+4]; p[0] != v || p[1] != v || p[2] != v || p[3] != 0xff {
+			t.Errorf("pixel %d = %v, want gray %d", i, p, v)
+		}
+	}
+}
+
+func TestResizePremultipliedAlpha(t *testing.T) {
+	// the color of a transparent pixel must not bleed into its neighbours.
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{R: 0xff, A: 0})
+	src.SetNRGBA(1, 0, color.NRGBA{B: 0xff, A: 0xff})
+	got := color.NRGBAModel.Convert(Resize(src, 1, 1).At(0, 0)).(color.NRGBA)
+	if got.R != 0 || got.G != 0 || got.B != 0xff || got.A != 0x80 {
+		t.Errorf("Resize() = %v, want half transparent blue", got)
+	}
+}
+
+func TestResizeOffsetBounds(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 1))
+	copy(src.Pix, grayRow(10, 20, 30, 40).Pix)
+	sub := src.SubImage(image.Rect(2, 0, 4, 1))
+	if got := grayLevels(Resize(sub, 1, 1)); got[0] != 35 {
+		t.Errorf("Resize() of a sub image = %v, want [35]", got)
+	}
+}
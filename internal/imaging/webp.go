@@ -0,0 +1,594 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+)
+
+// EncodeWebP writes the image as a lossless WebP (VP8L) image.
+// the encoder applies the subtract green and predictor transforms and
+// codes the residuals with backward references and per-channel
+// prefix codes, without a color cache. the output is deterministic.
+func EncodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > 1<<14 || height > 1<<14 {
+		return errors.New("webp: invalid image size")
+	}
+
+	pixels := make([]argb, 0, width*height)
+	alphaUsed := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			// fully transparent pixels are written as transparent black, which compresses better.
+			if c.A == 0 {
+				c = color.NRGBA{}
+			}
+			if c.A != 0xff {
+				alphaUsed = true
+			}
+			pixels = append(pixels, argb{c.A, c.R, c.G, c.B})
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.writeBits(uint32(width-1), 14)
+	bw.writeBits(uint32(height-1), 14)
+	if alphaUsed {
+		bw.writeBits(1, 1)
+	} else {
+		bw.writeBits(0, 1)
+	}
+	bw.writeBits(0, 3) // version
+
+	// subtract green transform.
+	bw.writeBits(1, 1)
+	bw.writeBits(transformSubtractGreen, 2)
+	for i, p := range pixels {
+		pixels[i].r = p.r - p.g
+		pixels[i].b = p.b - p.g
+	}
+
+	// predictor transform, the mode of every block is coded in a sub image.
+	bw.writeBits(1, 1)
+	bw.writeBits(transformPredictor, 2)
+	bw.writeBits(predictorBlockBits-2, 3)
+	modes, residuals := predict(pixels, width, height)
+	writeImageStream(bw, modes, (width+predictorBlockSize-1)/predictorBlockSize, false)
+
+	bw.writeBits(0, 1) // no more transforms
+	writeImageStream(bw, residuals, width, true)
+
+	data := bw.bytes()
+	chunkSize := 1 + len(data)
+	padding := chunkSize & 1
+	header := make([]byte, 0, 21)
+	header = append(header, "RIFF"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(4+8+chunkSize+padding))
+	header = append(header, "WEBPVP8L"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(chunkSize))
+	header = append(header, 0x2f) // VP8L signature
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if padding == 1 {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+// argb is a VP8L pixel, the arithmetic on the channels wraps around.
+type argb struct {
+	a, r, g, b uint8
+}
+
+const (
+	transformPredictor     = 0
+	transformSubtractGreen = 2
+
+	// the predictor modes are chosen per block of 16x16 pixels.
+	predictorBlockBits = 4
+	predictorBlockSize = 1 << predictorBlockBits
+
+	// the length and distance limits of the backward references.
+	minCopyLength   = 3
+	maxCopyLength   = 4096
+	maxCopyDistance = 1 << 18
+	maxChainDepth   = 32
+
+	// the distance codes up to 120 are reserved for the 2D neighborhood of the pixel.
+	distanceCodeOffset = 120
+)
+
+// predictorModes are the predictor modes the encoder chooses from:
+// 1 (left), 2 (top), 11 (select) and 12 (clamp add subtract full).
+var predictorModes = []uint8{1, 2, 11, 12}
+
+// predict chooses the predictor mode of every block by the smallest residuals,
+// and returns the modes sub image and the residuals.
+func predict(pixels []argb, width, height int) ([]argb, []argb) {
+	blocksX := (width + predictorBlockSize - 1) / predictorBlockSize
+	blocksY := (height + predictorBlockSize - 1) / predictorBlockSize
+	modes := make([]argb, blocksX*blocksY)
+	residuals := make([]argb, len(pixels))
+	for by := 0; by < blocksY; by++ {
+		for bx := 0; bx < blocksX; bx++ {
+			best, bestCost := predictorModes[0], -1
+			for _, mode := range predictorModes {
+				cost := 0
+				for y := by * predictorBlockSize; y < min(height, (by+1)*predictorBlockSize); y++ {
+					for x := bx * predictorBlockSize; x < min(width, (bx+1)*predictorBlockSize); x++ {
+						cost += residualCost(sub(pixels[y*width+x], prediction(pixels, width, x, y, mode)))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+			modes[by*blocksX+bx] = argb{a: 0xff, g: best}
+			for y := by * predictorBlockSize; y < min(height, (by+1)*predictorBlockSize); y++ {
+				for x := bx * predictorBlockSize; x < min(width, (bx+1)*predictorBlockSize); x++ {
+					residuals[y*width+x] = sub(pixels[y*width+x], prediction(pixels, width, x, y, best))
+				}
+			}
+		}
+	}
+	return modes, residuals
+}
+
+// prediction returns the prediction of the pixel at x, y.
+// the first pixel is predicted as opaque black, the first row from the left
+// and the first column from the top pixel, regardless of the mode.
+func prediction(pixels []argb, width, x, y int, mode uint8) argb {
+	switch {
+	case x == 0 && y == 0:
+		return argb{a: 0xff}
+	case y == 0:
+		return pixels[x-1]
+	case x == 0:
+		return pixels[(y-1)*width]
+	}
+	l, t, tl := pixels[y*width+x-1], pixels[(y-1)*width+x], pixels[(y-1)*width+x-1]
+	switch mode {
+	case 1:
+		return l
+	case 2:
+		return t
+	case 11:
+		return selectPredictor(l, t, tl)
+	default:
+		return argb{
+			clampAddSubtract(l.a, t.a, tl.a),
+			clampAddSubtract(l.r, t.r, tl.r),
+			clampAddSubtract(l.g, t.g, tl.g),
+			clampAddSubtract(l.b, t.b, tl.b),
+		}
+	}
+}
+
+// selectPredictor returns the left or the top pixel, the one closer to the gradient estimate L + T - TL.
+// the distance of the left pixel to the estimate is |T - TL|, of the top pixel |L - TL|.
+func selectPredictor(l, t, tl argb) argb {
+	pl := abs(int(t.a)-int(tl.a)) + abs(int(t.r)-int(tl.r)) + abs(int(t.g)-int(tl.g)) + abs(int(t.b)-int(tl.b))
+	pt := abs(int(l.a)-int(tl.a)) + abs(int(l.r)-int(tl.r)) + abs(int(l.g)-int(tl.g)) + abs(int(l.b)-int(tl.b))
+	if pl < pt {
+		return l
+	}
+	return t
+}
+
+func clampAddSubtract(a, b, c uint8) uint8 {
+	return uint8(min(255, max(0, int(a)+int(b)-int(c))))
+}
+
+func sub(p, q argb) argb {
+	return argb{p.a - q.a, p.r - q.r, p.g - q.g, p.b - q.b}
+}
+
+// residualCost estimates the cost of coding the residual, small signed values are cheap.
+func residualCost(p argb) int {
+	return abs(int(int8(p.a))) + abs(int(int8(p.r))) + abs(int(int8(p.g))) + abs(int(int8(p.b)))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// lz77Token is a literal pixel or a backward reference.
+type lz77Token struct {
+	pixel    argb
+	length   int // 0 for a literal
+	distance int
+}
+
+// writeImageStream writes the entropy coded image, the main image (level0)
+// has the meta prefix codes flag, the sub images do not.
+func writeImageStream(bw *bitWriter, pixels []argb, width int, level0 bool) {
+	bw.writeBits(0, 1) // no color cache
+	if level0 {
+		bw.writeBits(0, 1) // no meta prefix codes
+	}
+
+	// backward references, the candidates are the previous pixel, the row above
+	// and the earlier positions with the same next pixels (hash chain).
+	var tokens []lz77Token
+	head := make(map[[minCopyLength]argb]int)
+	prevPos := make([]int, len(pixels))
+	insert := func(i int) {
+		if i+minCopyLength > len(pixels) {
+			return
+		}
+		key := [minCopyLength]argb(pixels[i : i+minCopyLength])
+		if p, ok := head[key]; ok {
+			prevPos[i] = p
+		} else {
+			prevPos[i] = -1
+		}
+		head[key] = i
+	}
+	matchLength := func(i, distance int) int {
+		length := 0
+		for i+length < len(pixels) && length < maxCopyLength && pixels[i+length] == pixels[i+length-distance] {
+			length++
+		}
+		return length
+	}
+	for i := 0; i < len(pixels); {
+		bestLength, bestDistance := 0, 0
+		candidates := []int{1, width}
+		if i+minCopyLength <= len(pixels) {
+			if p, ok := head[[minCopyLength]argb(pixels[i:i+minCopyLength])]; ok {
+				for depth := 0; p >= 0 && depth < maxChainDepth && i-p <= maxCopyDistance; depth++ {
+					candidates = append(candidates, i-p)
+					p = prevPos[p]
+				}
+			}
+		}
+		for _, distance := range candidates {
+			if distance > i {
+				continue
+			}
+			if length := matchLength(i, distance); length > bestLength {
+				bestLength, bestDistance = length, distance
+			}
+		}
+		if bestLength >= minCopyLength {
+			tokens = append(tokens, lz77Token{length: bestLength, distance: bestDistance})
+			for k := 0; k < bestLength; k++ {
+				insert(i + k)
+			}
+			i += bestLength
+			continue
+		}
+		tokens = append(tokens, lz77Token{pixel: pixels[i]})
+		insert(i)
+		i++
+	}
+
+	histograms := [5][]int{
+		make([]int, 256+24), // green and the length prefixes
+		make([]int, 256),    // red
+		make([]int, 256),    // blue
+		make([]int, 256),    // alpha
+		make([]int, 40),     // distance prefixes
+	}
+	for _, t := range tokens {
+		if t.length == 0 {
+			histograms[0][t.pixel.g]++
+			histograms[1][t.pixel.r]++
+			histograms[2][t.pixel.b]++
+			histograms[3][t.pixel.a]++
+			continue
+		}
+		lengthCode, _, _ := prefixEncode(t.length)
+		histograms[0][256+lengthCode]++
+		distanceCode, _, _ := prefixEncode(t.distance + distanceCodeOffset)
+		histograms[4][distanceCode]++
+	}
+	var codes [5]prefixCode
+	for i := range codes {
+		codes[i] = writePrefixCode(bw, histograms[i])
+	}
+
+	for _, t := range tokens {
+		if t.length == 0 {
+			codes[0].write(bw, int(t.pixel.g))
+			codes[1].write(bw, int(t.pixel.r))
+			codes[2].write(bw, int(t.pixel.b))
+			codes[3].write(bw, int(t.pixel.a))
+			continue
+		}
+		code, extra, extraBits := prefixEncode(t.length)
+		codes[0].write(bw, 256+code)
+		bw.writeBits(uint32(extra), uint(extraBits))
+		code, extra, extraBits = prefixEncode(t.distance + distanceCodeOffset)
+		codes[4].write(bw, code)
+		bw.writeBits(uint32(extra), uint(extraBits))
+	}
+}
+
+// prefixEncode returns the prefix code and the extra bits of a length or distance value (>= 1).
+func prefixEncode(value int) (code, extra, extraBits int) {
+	n := value - 1
+	if n < 4 {
+		return n, 0, 0
+	}
+	highest := 0
+	for n>>(highest+1) != 0 {
+		highest++
+	}
+	second := (n >> (highest - 1)) & 1
+	extraBits = highest - 1
+	return 2*highest + second, n & (1<<extraBits - 1), extraBits
+}
+
+// bitWriter writes the bits in the LSB first order used by VP8L.
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (bw *bitWriter) writeBits(v uint32, n uint) {
+	bw.acc |= uint64(v) << bw.nbits
+	bw.nbits += n
+	for bw.nbits >= 8 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc >>= 8
+		bw.nbits -= 8
+	}
+}
+
+func (bw *bitWriter) bytes() []byte {
+	if bw.nbits > 0 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc, bw.nbits = 0, 0
+	}
+	return bw.buf
+}
+
+// prefixCode is a canonical prefix code, the codes are stored bit reversed
+// as they are written LSB first.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint32
+}
+
+func (pc prefixCode) write(bw *bitWriter, symbol int) {
+	if n := pc.lengths[symbol]; n > 0 {
+		bw.writeBits(pc.codes[symbol], uint(n))
+	}
+}
+
+// writePrefixCode writes the prefix code of the histogram and returns it.
+// a code with at most two symbols below 256 is written as a simple code.
+// a code with a single symbol is read with zero bits.
+func writePrefixCode(bw *bitWriter, histogram []int) prefixCode {
+	var symbols []int
+	for s, n := range histogram {
+		if n > 0 {
+			symbols = append(symbols, s)
+		}
+	}
+	if len(symbols) == 0 {
+		symbols = []int{0}
+	}
+	if len(symbols) <= 2 && symbols[len(symbols)-1] < 256 {
+		return writeSimpleCode(bw, symbols, len(histogram))
+	}
+
+	lengths := huffmanLengths(histogram, 15)
+	bw.writeBits(0, 1) // normal code
+
+	// the code lengths are written with the code length code,
+	// 16 repeats the previous non zero length, 17 and 18 repeat zeros.
+	type token struct{ symbol, extra, extraBits int }
+	var tokens []token
+	var clHistogram [19]int
+	prev := 8
+	for i := 0; i < len(lengths); {
+		l := int(lengths[i])
+		run := 1
+		for i+run < len(lengths) && int(lengths[i+run]) == l {
+			run++
+		}
+		switch {
+		case l == 0 && run >= 11:
+			run = min(run, 138)
+			tokens = append(tokens, token{18, run - 11, 7})
+		case l == 0 && run >= 3:
+			run = min(run, 10)
+			tokens = append(tokens, token{17, run - 3, 3})
+		case l != 0 && l == prev && run >= 3:
+			run = min(run, 6)
+			tokens = append(tokens, token{16, run - 3, 2})
+		default:
+			run = 1
+			tokens = append(tokens, token{l, 0, 0})
+			if l != 0 {
+				prev = l
+			}
+		}
+		clHistogram[tokens[len(tokens)-1].symbol]++
+		i += run
+	}
+
+	clLengths := huffmanLengths(clHistogram[:], 7)
+	// pair a single code length symbol with another symbol of the same length,
+	// so every token is written with one bit.
+	if countUsed(clLengths) == 1 {
+		for s, l := range clLengths {
+			if l == 0 {
+				clLengths[s] = 1
+				break
+			}
+		}
+		for s, l := range clLengths {
+			if l > 0 {
+				clLengths[s] = 1
+			}
+		}
+	}
+	clCode := canonicalCode(clLengths)
+
+	bw.writeBits(uint32(len(codeLengthCodeOrder)-4), 4)
+	for _, s := range codeLengthCodeOrder {
+		bw.writeBits(uint32(clLengths[s]), 3)
+	}
+	bw.writeBits(0, 1) // the lengths of all the symbols are written
+	for _, t := range tokens {
+		clCode.write(bw, t.symbol)
+		if t.extraBits > 0 {
+			bw.writeBits(uint32(t.extra), uint(t.extraBits))
+		}
+	}
+	if countUsed(lengths) == 1 {
+		return prefixCode{lengths: make([]uint8, len(lengths)), codes: make([]uint32, len(lengths))}
+	}
+	return canonicalCode(lengths)
+}
+
+func countUsed(lengths []uint8) int {
+	used := 0
+	for _, l := range lengths {
+		if l > 0 {
+			used++
+		}
+	}
+	return used
+}
+
+// writeSimpleCode writes a simple code of one or two symbols below 256.
+func writeSimpleCode(bw *bitWriter, symbols []int, alphabetSize int) prefixCode {
+	bw.writeBits(1, 1) // simple code
+	bw.writeBits(uint32(len(symbols)-1), 1)
+	if symbols[0] <= 1 {
+		bw.writeBits(0, 1)
+		bw.writeBits(uint32(symbols[0]), 1)
+	} else {
+		bw.writeBits(1, 1)
+		bw.writeBits(uint32(symbols[0]), 8)
+	}
+	if len(symbols) == 2 {
+		bw.writeBits(uint32(symbols[1]), 8)
+	}
+	lengths := make([]uint8, alphabetSize)
+	if len(symbols) == 1 {
+		return prefixCode{lengths: lengths, codes: make([]uint32, alphabetSize)}
+	}
+	for _, s := range symbols {
+		lengths[s] = 1
+	}
+	return canonicalCode(lengths)
+}
+
+// codeLengthCodeOrder is the order the code length code lengths are written in.
+var codeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// canonicalCode assigns the canonical codes to the code lengths.
+func canonicalCode(lengths []uint8) prefixCode {
+	var count [16]uint32
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+		}
+	}
+	var next [16]uint32
+	code := uint32(0)
+	for l := 1; l < 16; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+	codes := make([]uint32, len(lengths))
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		c := next[l]
+		next[l]++
+		// reverse the code, it is written LSB first.
+		r := uint32(0)
+		for i := uint8(0); i < l; i++ {
+			r = r<<1 | (c>>i)&1
+		}
+		codes[s] = r
+	}
+	return prefixCode{lengths: lengths, codes: codes}
+}
+
+// huffmanLengths returns the huffman code lengths of the histogram limited to maxLength.
+// when the limit is exceeded, the small counts are raised and the code is built again.
+func huffmanLengths(histogram []int, maxLength int) []uint8 {
+	for floor := 0; ; floor = max(1, floor*2) {
+		lengths := buildHuffmanLengths(histogram, floor)
+		longest := uint8(0)
+		for _, l := range lengths {
+			longest = max(longest, l)
+		}
+		if int(longest) <= maxLength {
+			return lengths
+		}
+	}
+}
+
+// buildHuffmanLengths builds the huffman code lengths, the non zero counts are raised to the floor.
+func buildHuffmanLengths(histogram []int, floor int) []uint8 {
+	type node struct {
+		count       int
+		symbol      int // the smallest symbol in the subtree, for a stable order
+		left, right int // children indexes, -1 for the leaves
+	}
+	var nodes []node
+	var queue []int
+	for s, n := range histogram {
+		if n > 0 {
+			nodes = append(nodes, node{count: max(n, floor), symbol: s, left: -1, right: -1})
+			queue = append(queue, len(nodes)-1)
+		}
+	}
+	lengths := make([]uint8, len(histogram))
+	if len(queue) == 1 {
+		lengths[nodes[0].symbol] = 1
+		return lengths
+	}
+	less := func(a, b int) bool {
+		if nodes[a].count != nodes[b].count {
+			return nodes[a].count < nodes[b].count
+		}
+		return nodes[a].symbol < nodes[b].symbol
+	}
+	for len(queue) > 1 {
+		sort.Slice(queue, func(i, j int) bool { return less(queue[i], queue[j]) })
+		a, b := queue[0], queue[1]
+		nodes = append(nodes, node{
+			count:  nodes[a].count + nodes[b].count,
+			symbol: min(nodes[a].symbol, nodes[b].symbol),
+			left:   a,
+			right:  b,
+		})
+		queue = append(queue[2:], len(nodes)-1)
+	}
+	var walk func(i int, depth uint8)
+	walk = func(i int, depth uint8) {
+		if nodes[i].left < 0 {
+			lengths[nodes[i].symbol] = depth
+			return
+		}
+		walk(nodes[i].left, depth+1)
+		walk(nodes[i].right, depth+1)
+	}
+	walk(queue[0], 0)
+	return lengths
+}
@@ -0,0 +1,143 @@
+package imaging
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"math/rand/v2"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+// testImages returns the images the encoder is tested with, by name.
+func testImages() map[string]image.Image {
+	images := make(map[string]image.Image)
+
+	gradient := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 4), G: uint8(y * 4), B: uint8(x ^ y), A: 0xff})
+		}
+	}
+	images["opaque gradient"] = gradient
+
+	// a round logo on a transparent background with an antialiased edge.
+	logo := image.NewNRGBA(image.Rect(0, 0, 48, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 48; x++ {
+			d := (x-24)*(x-24) + (y-24)*(y-24)
+			switch {
+			case d < 20*20:
+				logo.SetNRGBA(x, y, color.NRGBA{R: 0x26, G: 0xa1, B: 0x7b, A: 0xff})
+			case d < 21*21:
+				logo.SetNRGBA(x, y, color.NRGBA{R: 0x26, G: 0xa1, B: 0x7b, A: 0x80})
+			}
+		}
+	}
+	images["transparent logo"] = logo
+
+	solid := image.NewNRGBA(image.Rect(0, 0, 32, 32))
+	for i := range solid.Pix {
+		solid.Pix[i] = []uint8{0x12, 0x34, 0x56, 0xff}[i%4]
+	}
+	images["solid color"] = solid
+
+	// the random pixels have few repetitions and use every symbol of the prefix codes.
+	rng := rand.New(rand.NewPCG(1, 2))
+	noise := image.NewNRGBA(image.Rect(0, 0, 37, 23))
+	for i := range noise.Pix {
+		noise.Pix[i] = uint8(rng.IntN(256))
+	}
+	images["noise with odd size"] = noise
+
+	// the repeated random rows are coded with backward references.
+	repeated := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		copy(repeated.Pix[y*repeated.Stride:], noise.Pix[(y%3)*noise.Stride:(y%3)*noise.Stride+40*4])
+	}
+	images["repeated rows"] = repeated
+
+	images["single pixel"] = image.NewNRGBA(image.Rect(0, 0, 1, 1))
+
+	offset := image.NewRGBA(image.Rect(10, 20, 26, 36))
+	for i := range offset.Pix {
+		offset.Pix[i] = uint8(i)
+		if i%4 == 3 {
+			offset.Pix[i] = 0xff
+		}
+	}
+	images["premultiplied with offset bounds"] = offset
+
+	images["resized logo"] = Resize(logo, 32, 32)
+	return images
+}
+
+func TestEncodeWebP(t *testing.T) {
+	for name, src := range testImages() {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeWebP(&buf, src); err != nil {
+				t.Fatalf("EncodeWebP() error = %v", err)
+			}
+			decoded, err := webp.Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("webp.Decode() error = %v", err)
+			}
+			bounds := src.Bounds()
+			if decoded.Bounds().Dx() != bounds.Dx() || decoded.Bounds().Dy() != bounds.Dy() {
+				t.Fatalf("decoded size = %v, want %v", decoded.Bounds().Size(), bounds.Size())
+			}
+			for y := 0; y < bounds.Dy(); y++ {
+				for x := 0; x < bounds.Dx(); x++ {
+					want := color.NRGBAModel.Convert(src.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+					if want.A == 0 {
+						// the fully transparent pixels are written as transparent black.
+						want = color.NRGBA{}
+					}
+					got := color.NRGBAModel.Convert(decoded.At(decoded.Bounds().Min.X+x, decoded.Bounds().Min.Y+y)).(color.NRGBA)
+					if got != want {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeWebPDeterministic(t *testing.T) {
+	src := testImages()["transparent logo"]
+	var first, second bytes.Buffer
+	if err := EncodeWebP(&first, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncodeWebP(&second, src); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Fatalf("EncodeWebP() output differs between runs")
+	}
+	// the published logos must not change unless their sources change, the golden hash
+	// catches an encoder change that would rewrite every published WebP.
+	sum := sha256.Sum256(first.Bytes())
+	if got := hex.EncodeToString(sum[:]); got != goldenWebPSha256 {
+		t.Errorf("EncodeWebP() sha256 = %s, want %s", got, goldenWebPSha256)
+	}
+}
+
+// goldenWebPSha256 is the sha256 of the transparent logo of the tests encoded as WebP.
+const goldenWebPSha256 = "f27e787e548061119a5a4b139baad5d46323bb93eb42d8f35ab5a2affeb3322c"
+
+func TestEncodeWebPInvalidSize(t *testing.T) {
+	for _, rect := range []image.Rectangle{
+		image.Rect(0, 0, 0, 10),
+		image.Rect(0, 0, 10, 0),
+		image.Rect(0, 0, 1<<14+1, 1),
+	} {
+		if err := EncodeWebP(&bytes.Buffer{}, image.NewNRGBA(rect)); err == nil {
+			t.Errorf("EncodeWebP(%v) error = nil, want an invalid size", rect)
+		}
+	}
+}
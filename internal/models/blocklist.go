@@ -0,0 +1,40 @@
+package models
+
+// BlockReason is the reason a token address is in the blocklist.
+type BlockReason string
+
+const (
+	// The token is flagged as a scam.
+	BlockReason_SCAM BlockReason = "scam"
+
+	// The token is disabled (e.g., due to a security issue).
+	BlockReason_DISABLED BlockReason = "disabled"
+)
+
+// Blocklist is the model for the blocked token addresses (blocklist.json).
+// the blocked tokens are not listed, the wallets warn the users holding them.
+type Blocklist struct {
+	// The blocked addresses by the network id, sorted by the address.
+	Networks map[int64][]BlockedAddress `json:"networks"`
+}
+
+// BlockedAddress is the model for a blocked token address.
+type BlockedAddress struct {
+	// The canonical address of the token on the network, refer to Network.CanonicalAddress.
+	Address string `json:"address"`
+
+	// The CAIP-19 asset id of the address, empty if it has none.
+	Caip19 string `json:"caip19,omitempty"`
+
+	// The uuid of the token.
+	TokenUuid string `json:"token_uuid"`
+
+	// The symbol of the token.
+	Symbol string `json:"symbol"`
+
+	// The name of the token.
+	Name string `json:"name"`
+
+	// Why the address is blocked, a scam token is reported as a scam even if it is disabled.
+	Reason BlockReason `json:"reason"`
+}
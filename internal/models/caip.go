@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The CAIP-2 references of the non-EVM networks, refer to the namespaces at https://namespaces.chainagnostic.org.
+var (
+	// the key is the chain id, the value is the truncated genesis hash.
+	solanaCaip2References = map[int64]string{
+		101: "5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp",
+		102: "4uhcVJyU9pJkvQyS88uRDiswHXSRkM3u",
+		103: "EtWTRABZaYq6iMfeYKouRu166VU2xqa1",
+	}
+
+	// the key is the coin type, the value is the truncated genesis block hash.
+	bip122Caip2References = map[Coin_Type]string{
+		Coin_TYPE_BTC: "000000000019d6689c085ae165831e93",
+	}
+)
+
+// caip19AssetReference is the allowed asset reference of CAIP-19.
+var caip19AssetReference = regexp.MustCompile(`^[-.%a-zA-Z0-9]{1,128}$`)
+
+// CAIP2 returns the CAIP-2 chain id of the network (e.g., "eip155:1").
+// it returns an error if the network type has no CAIP-2 namespace or the chain is unknown.
+func (n Network) CAIP2() (string, error) {
+	switch n.NetworkType {
+	case NetworkType_NETWORK_TYPE_ETH_LIKE:
+		if n.ChainId <= 0 {
+			return "", fmt.Errorf("network %d has no chain id", n.Id)
+		}
+		return fmt.Sprintf("eip155:%d", n.ChainId), nil
+	case NetworkType_NETWORK_TYPE_TRX:
+		if n.ChainId <= 0 {
+			return "", fmt.Errorf("network %d has no chain id", n.Id)
+		}
+		return fmt.Sprintf("tron:0x%x", n.ChainId), nil
+	case NetworkType_NETWORK_TYPE_SOL:
+		if reference, ok := solanaCaip2References[n.ChainId]; ok {
+			return "solana:" + reference, nil
+		}
+		return "", fmt.Errorf("network %d has an unknown solana chain id: %d", n.Id, n.ChainId)
+	case NetworkType_NETWORK_TYPE_UTXO:
+		if reference, ok := bip122Caip2References[n.CoinType]; ok {
+			return "bip122:" + reference, nil
+		}
+		return "", fmt.Errorf("network %d has an unknown UTXO coin type: %d", n.Id, n.CoinType)
+	default:
+		return "", fmt.Errorf("network %d has no CAIP-2 namespace for the network type %d", n.Id, n.NetworkType)
+	}
+}
+
+// CAIP19 returns the CAIP-19 asset id of the token address on the network:
+// - the native tokens are "<caip2>/slip44:<coin type>".
+// - the ethereum-like tokens are "<caip2>/erc20:<lowercase address>" (or erc721, erc1155).
+// - the solana tokens are "<caip2>/token:<mint>".
+// - the tron tokens are "<caip2>/trc20:<address>".
+// it returns an error if the asset has no CAIP-19 asset id.
+func (n Network) CAIP19(address TokenAddress) (string, error) {
+	caip2, err := n.CAIP2()
+	if err != nil {
+		return "", err
+	}
+	if address.IsNative {
+		return fmt.Sprintf("%s/slip44:%d", caip2, n.CoinType), nil
+	}
+	var namespace, reference string
+	switch n.NetworkType {
+	case NetworkType_NETWORK_TYPE_ETH_LIKE:
+		namespace = strings.ToLower(address.TokenType)
+		if namespace != "erc20" && namespace != "erc721" && namespace != "erc1155" {
+			return "", fmt.Errorf("token type %q has no CAIP-19 asset namespace on network %d", address.TokenType, n.Id)
+		}
+		reference = n.CanonicalAddress(address.Address)
+	case NetworkType_NETWORK_TYPE_SOL:
+		namespace, reference = "token", address.Address
+	case NetworkType_NETWORK_TYPE_TRX:
+		namespace, reference = "trc20", address.Address
+	default:
+		return "", fmt.Errorf("network %d has no CAIP-19 asset namespace for the tokens", n.Id)
+	}
+	if !caip19AssetReference.MatchString(reference) {
+		return "", fmt.Errorf("address %q is not a valid CAIP-19 asset reference", address.Address)
+	}
+	return fmt.Sprintf("%s/%s:%s", caip2, namespace, reference), nil
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ExplorerKind is the kind of an explorer page.
+type ExplorerKind int
+
+const (
+	// The page of an address (an account or a wallet).
+	ExplorerKind_ADDRESS ExplorerKind = iota
+	// The page of a transaction.
+	ExplorerKind_TRANSACTION
+	// The page of a token (contract, mint or asset).
+	ExplorerKind_TOKEN
+	// The page of a block.
+	ExplorerKind_BLOCK
+)
+
+// ExplorerKinds are all explorer kinds.
+var ExplorerKinds = []ExplorerKind{
+	ExplorerKind_ADDRESS, ExplorerKind_TRANSACTION, ExplorerKind_TOKEN, ExplorerKind_BLOCK,
+}
+
+func (k ExplorerKind) String() string {
+	switch k {
+	case ExplorerKind_ADDRESS:
+		return "address"
+	case ExplorerKind_TRANSACTION:
+		return "transaction"
+	case ExplorerKind_TOKEN:
+		return "token"
+	case ExplorerKind_BLOCK:
+		return "block"
+	default:
+		return fmt.Sprintf("explorer_kind(%d)", int(k))
+	}
+}
+
+// Template returns the template of the explorer page of the kind, empty if the explorer has none.
+func (e Explorer) Template(kind ExplorerKind) string {
+	switch kind {
+	case ExplorerKind_ADDRESS:
+		return e.AddressTemplate
+	case ExplorerKind_TRANSACTION:
+		return e.TransactionTemplate
+	case ExplorerKind_TOKEN:
+		return e.TokenTemplate
+	case ExplorerKind_BLOCK:
+		return e.BlockTemplate
+	default:
+		return ""
+	}
+}
+
+// SupportsExplorerKind reports whether the network type has pages of the kind,
+// the UTXO networks have no tokens, so they have no token pages.
+func (t NetworkType) SupportsExplorerKind(kind ExplorerKind) bool {
+	if kind == ExplorerKind_TOKEN {
+		return t != NetworkType_NETWORK_TYPE_UTXO
+	}
+	return true
+}
+
+// ValidateExplorer validates the explorer of the network:
+// - the base URL is an http(s) URL without a path suffix slash, query or fragment.
+// - every page the network type supports has a template, the others have none.
+// - a template starts with "/" and has exactly one %s placeholder and no other verbs.
+// it returns an error if any.
+func (n Network) ValidateExplorer() error {
+	base, err := url.Parse(n.Explorer.BaseUrl)
+	if err != nil || (base.Scheme != "https" && base.Scheme != "http") || base.Host == "" {
+		return fmt.Errorf("explorer base URL must be an http(s) URL, got: %q", n.Explorer.BaseUrl)
+	}
+	if strings.HasSuffix(base.Path, "/") || base.RawQuery != "" || base.Fragment != "" {
+		return fmt.Errorf("explorer base URL must not end with a slash, query or fragment, got: %q", n.Explorer.BaseUrl)
+	}
+	for _, kind := range ExplorerKinds {
+		template := n.Explorer.Template(kind)
+		if !n.NetworkType.SupportsExplorerKind(kind) {
+			if template != "" {
+				return fmt.Errorf("explorer %s template must be empty for the network type %d, got: %q", kind, n.NetworkType, template)
+			}
+			continue
+		}
+		if err := validateExplorerTemplate(template); err != nil {
+			return fmt.Errorf("explorer %s template %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// validateExplorerTemplate validates that the template is a path with exactly one %s placeholder.
+func validateExplorerTemplate(template string) error {
+	if template == "" {
+		return fmt.Errorf("is required")
+	}
+	if !strings.HasPrefix(template, "/") {
+		return fmt.Errorf("must start with /, got: %q", template)
+	}
+	if strings.Count(template, "%s") != 1 || strings.Count(template, "%") != 1 {
+		return fmt.Errorf("must have exactly one %%s placeholder and no other verbs, got: %q", template)
+	}
+	return nil
+}
+
+// ExplorerURL returns the URL of the explorer page of the value (an address, a transaction hash,
+// a token address or a block number or hash) on the network, the value is escaped as a path segment.
+// it returns an error if the network has no valid explorer page of the kind.
+func (n Network) ExplorerURL(kind ExplorerKind, value string) (string, error) {
+	if !n.NetworkType.SupportsExplorerKind(kind) {
+		return "", fmt.Errorf("network %d has no %s explorer pages", n.Id, kind)
+	}
+	template := n.Explorer.Template(kind)
+	if err := validateExplorerTemplate(template); err != nil {
+		return "", fmt.Errorf("network %d explorer %s template %w", n.Id, kind, err)
+	}
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf("explorer %s value is required", kind)
+	}
+	return n.Explorer.BaseUrl + strings.Replace(template, "%s", url.PathEscape(value), 1), nil
+}
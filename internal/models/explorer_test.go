@@ -0,0 +1,102 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// testExplorer returns a valid explorer of an EVM network.
+func testExplorer() Explorer {
+	return Explorer{
+		BaseUrl:             "https://etherscan.io",
+		AddressTemplate:     "/address/%s",
+		TransactionTemplate: "/tx/%s",
+		TokenTemplate:       "/token/%s",
+		BlockTemplate:       "/block/%s",
+	}
+}
+
+func TestValidateExplorer(t *testing.T) {
+	tests := []struct {
+		name        string
+		networkType NetworkType
+		edit        func(e *Explorer)
+		want        string
+	}{
+		{"valid", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) {}, ""},
+		{"base URL with a path", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.BaseUrl = "https://explorer.example/mainnet" }, ""},
+		{"http base URL", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.BaseUrl = "http://explorer.example" }, ""},
+		{"no base URL", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.BaseUrl = "" }, "must be an http(s) URL"},
+		{"ftp base URL", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.BaseUrl = "ftp://etherscan.io" }, "must be an http(s) URL"},
+		{"base URL trailing slash", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.BaseUrl = "https://etherscan.io/" }, "must not end with a slash, query or fragment"},
+		{"base URL query", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.BaseUrl = "https://etherscan.io?chain=1" }, "must not end with a slash, query or fragment"},
+		{"base URL fragment", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.BaseUrl = "https://etherscan.io#top" }, "must not end with a slash, query or fragment"},
+		{"missing template", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.BlockTemplate = "" }, "explorer block template is required"},
+		{"no placeholder", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.AddressTemplate = "/address/" }, "explorer address template must have exactly one %s placeholder"},
+		{"two placeholders", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.TransactionTemplate = "/tx/%s/%s" }, "explorer transaction template must have exactly one %s placeholder"},
+		{"other verb", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.BlockTemplate = "/block/%d" }, "no other verbs"},
+		{"placeholder and other verb", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.TokenTemplate = "/token/%s?page=%d" }, "no other verbs"},
+		{"no leading slash", NetworkType_NETWORK_TYPE_ETH_LIKE, func(e *Explorer) { e.TokenTemplate = "token/%s" }, "explorer token template must start with /"},
+		{"UTXO without token template", NetworkType_NETWORK_TYPE_UTXO, func(e *Explorer) { e.TokenTemplate = "" }, ""},
+		{"UTXO with token template", NetworkType_NETWORK_TYPE_UTXO, func(e *Explorer) {}, "explorer token template must be empty for the network type 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := Network{Id: 1, NetworkType: tt.networkType, Explorer: testExplorer()}
+			tt.edit(&network.Explorer)
+			err := network.ValidateExplorer()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("ValidateExplorer() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ValidateExplorer() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplorerURL(t *testing.T) {
+	evm := Network{Id: 1, NetworkType: NetworkType_NETWORK_TYPE_ETH_LIKE, Explorer: testExplorer()}
+	utxo := Network{Id: 5, NetworkType: NetworkType_NETWORK_TYPE_UTXO, Explorer: testExplorer()}
+	utxo.Explorer.TokenTemplate = ""
+	broken := evm
+	broken.Explorer.AddressTemplate = "/address/%d"
+
+	tests := []struct {
+		name    string
+		network Network
+		kind    ExplorerKind
+		value   string
+		want    string
+		wantErr string
+	}{
+		{"address", evm, ExplorerKind_ADDRESS, "0xdac17f958d2ee523a2206206994597c13d831ec7", "https://etherscan.io/address/0xdac17f958d2ee523a2206206994597c13d831ec7", ""},
+		{"block number", evm, ExplorerKind_BLOCK, "19000000", "https://etherscan.io/block/19000000", ""},
+		{"utxo transaction", utxo, ExplorerKind_TRANSACTION, "abc", "https://etherscan.io/tx/abc", ""},
+		// the value is a single path segment.
+		{"escaped slash", evm, ExplorerKind_TOKEN, "../admin", "https://etherscan.io/token/..%2Fadmin", ""},
+		{"escaped query", evm, ExplorerKind_ADDRESS, "0x1?tab=2#top", "https://etherscan.io/address/0x1%3Ftab=2%23top", ""},
+		{"escaped space", evm, ExplorerKind_ADDRESS, "a b", "https://etherscan.io/address/a%20b", ""},
+		{"escaped percent", evm, ExplorerKind_ADDRESS, "%s", "https://etherscan.io/address/%25s", ""},
+		{"utxo token", utxo, ExplorerKind_TOKEN, "abc", "", "network 5 has no token explorer pages"},
+		{"invalid template", broken, ExplorerKind_ADDRESS, "0x1", "", "network 1 explorer address template must have exactly one %s placeholder"},
+		{"empty value", evm, ExplorerKind_ADDRESS, "  ", "", "explorer address value is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.network.ExplorerURL(tt.kind, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ExplorerURL() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ExplorerURL(%s, %q) = %q, %v, want %q", tt.kind, tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package models
+
+import "regexp"
+
+// DefaultLocale is the locale of the names and the descriptions in meta.json,
+// the localized artifacts fall back to it.
+const DefaultLocale = "en"
+
+// localeRegex matches the BCP-47 language tags of the form language[-Script][-REGION]
+// in their canonical case (e.g., "de", "zh-Hant", "pt-BR", "es-419").
+var localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|[0-9]{3}))?$`)
+
+// IsValidLocale reports whether the locale is a BCP-47 language tag of the form language[-Script][-REGION]
+// in its canonical case.
+func IsValidLocale(locale string) bool {
+	return localeRegex.MatchString(locale)
+}
+
+// Localization is the model for the translation of a token (tokens/:tokenUid/i18n/:locale.json).
+// the fields left empty fall back to the DefaultLocale.
+type Localization struct {
+	// The translated name of the token.
+	Name string `json:"name,omitempty"`
+
+	// The translated description of the token.
+	Description string `json:"description,omitempty"`
+}
+
+// I18nCoverage is the model for the translation coverage report.
+type I18nCoverage struct {
+	// The number of the listed tokens.
+	Tokens int `json:"tokens"`
+
+	// The coverage of every locale, sorted by the locale.
+	Locales []LocaleCoverage `json:"locales"`
+}
+
+// LocaleCoverage is the model for the translation coverage of a locale.
+type LocaleCoverage struct {
+	// The locale.
+	Locale string `json:"locale"`
+
+	// The number of the tokens with a translated name.
+	Names int `json:"names"`
+
+	// The number of the tokens with a translated description.
+	Descriptions int `json:"descriptions"`
+
+	// The tokens missing a translation, sorted by the order index.
+	Missing []MissingTranslation `json:"missing"`
+}
+
+// MissingTranslation is the model for a token missing a translation.
+type MissingTranslation struct {
+	// The uuid of the token.
+	TokenUuid string `json:"token_uuid"`
+
+	// The symbol of the token.
+	Symbol string `json:"symbol"`
+
+	// The missing fields, "name" and/or "description".
+	Fields []string `json:"fields"`
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LifecycleStatus is the lifecycle status of a token or a token address.
+type LifecycleStatus string
+
+const (
+	// The token is listed and supported, the zero value of a missing lifecycle.
+	LifecycleStatus_ACTIVE LifecycleStatus = "active"
+
+	// The token is still listed, but it is being phased out (e.g., a migration is announced).
+	LifecycleStatus_DEPRECATED LifecycleStatus = "deprecated"
+
+	// The token contract was replaced by the successor contract (e.g., a v1 to v2 token swap).
+	LifecycleStatus_MIGRATED LifecycleStatus = "migrated"
+
+	// The token is not listed anymore.
+	LifecycleStatus_DELISTED LifecycleStatus = "delisted"
+)
+
+// LifecycleStatuses is the list of the lifecycle statuses.
+var LifecycleStatuses = []LifecycleStatus{
+	LifecycleStatus_ACTIVE,
+	LifecycleStatus_DEPRECATED,
+	LifecycleStatus_MIGRATED,
+	LifecycleStatus_DELISTED,
+}
+
+// LifecycleDateLayout is the layout of the lifecycle dates (e.g., "2024-03-01").
+const LifecycleDateLayout = time.DateOnly
+
+// Lifecycle is the model for the lifecycle of a token or a token address.
+// the lifecycle of an address overrides the lifecycle of its token.
+type Lifecycle struct {
+	// The lifecycle status.
+	Status LifecycleStatus `json:"status"`
+
+	// The date the status took effect, formatted as LifecycleDateLayout.
+	// required unless the status is active.
+	Since string `json:"since,omitempty"`
+
+	// The address that replaces the token or the token address.
+	// required for the migrated status, optional for the deprecated status.
+	Successor *AddressRef `json:"successor,omitempty"`
+
+	// Why the status was set (e.g., "v2 token swap, 1:1 at https://...").
+	// required unless the status is active.
+	Reason string `json:"reason,omitempty"`
+}
+
+// AddressRef is the model for a reference to a token address of the registry.
+type AddressRef struct {
+	// The ID of the network.
+	NetworkId int32 `json:"network_id"`
+
+	// The address of the token on the network.
+	Address string `json:"address"`
+}
+
+// EffectiveStatus returns the status of the lifecycle, a nil lifecycle is active.
+func (l *Lifecycle) EffectiveStatus() LifecycleStatus {
+	if l == nil {
+		return LifecycleStatus_ACTIVE
+	}
+	return l.Status
+}
+
+// Clone returns a deep copy of the lifecycle.
+func (l *Lifecycle) Clone() *Lifecycle {
+	if l == nil {
+		return nil
+	}
+	c := *l
+	if l.Successor != nil {
+		successor := *l.Successor
+		c.Successor = &successor
+	}
+	return &c
+}
+
+// Validate validates the consistency of the lifecycle fields:
+// - status is one of LifecycleStatuses.
+// - since and reason are set unless the status is active, since is a valid date.
+// - successor is set for the migrated status, and only for the migrated and deprecated statuses.
+// it returns an error if any.
+func (l *Lifecycle) Validate() error {
+	if l == nil {
+		return nil
+	}
+	switch l.Status {
+	case LifecycleStatus_ACTIVE:
+		if l.Since != "" || l.Successor != nil || l.Reason != "" {
+			return errors.New("active status must not have a since date, successor or reason")
+		}
+		return nil
+	case LifecycleStatus_DEPRECATED, LifecycleStatus_MIGRATED, LifecycleStatus_DELISTED:
+	default:
+		return fmt.Errorf("unknown status %q, expected one of %v", l.Status, LifecycleStatuses)
+	}
+	if l.Since == "" {
+		return fmt.Errorf("%s status requires the since date", l.Status)
+	}
+	if _, err := time.Parse(LifecycleDateLayout, l.Since); err != nil {
+		return fmt.Errorf("since date must be formatted as YYYY-MM-DD, got: %q", l.Since)
+	}
+	if l.Reason == "" {
+		return fmt.Errorf("%s status requires a reason", l.Status)
+	}
+	switch {
+	case l.Status == LifecycleStatus_MIGRATED && l.Successor == nil:
+		return errors.New("migrated status requires a successor")
+	case l.Status == LifecycleStatus_DELISTED && l.Successor != nil:
+		return errors.New("delisted status must not have a successor, use the migrated status")
+	}
+	return nil
+}
+
+// Migrations is the model for the migration map (migrations.json).
+// the wallets prompt the users holding an old contract to move to its successor.
+type Migrations struct {
+	// The migrated and deprecated addresses with a successor by the network id, sorted by the address.
+	Networks map[int64][]Migration `json:"networks"`
+}
+
+// Migration is the model for the migration of a token address to its successor.
+type Migration struct {
+	// The canonical address of the old contract, refer to Network.CanonicalAddress.
+	Address string `json:"address"`
+
+	// The CAIP-19 asset id of the old contract.
+	Caip19 string `json:"caip19,omitempty"`
+
+	// The uuid of the token of the old contract.
+	TokenUuid string `json:"token_uuid"`
+
+	// The symbol of the token of the old contract.
+	Symbol string `json:"symbol"`
+
+	// The lifecycle status of the old contract, migrated or deprecated.
+	Status LifecycleStatus `json:"status"`
+
+	// The date the status took effect, formatted as LifecycleDateLayout.
+	Since string `json:"since"`
+
+	// Why the contract was migrated or deprecated.
+	Reason string `json:"reason"`
+
+	// The successor of the old contract.
+	Successor MigrationTarget `json:"successor"`
+}
+
+// MigrationTarget is the model for the successor of a migrated token address.
+type MigrationTarget struct {
+	// The ID of the network.
+	NetworkId int32 `json:"network_id"`
+
+	// The canonical address of the successor, refer to Network.CanonicalAddress.
+	Address string `json:"address"`
+
+	// The CAIP-19 asset id of the successor.
+	Caip19 string `json:"caip19,omitempty"`
+
+	// The uuid of the token of the successor.
+	TokenUuid string `json:"token_uuid"`
+
+	// The symbol of the token of the successor.
+	Symbol string `json:"symbol"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// LogoHashes is the model for the perceptual hashes of the logos (logo_hashes.json).
+// the clients can hash the logo of a non-listed token and compare it against
+// the listed tokens to detect the impersonation.
+type LogoHashes struct {
+	// The hash algorithm (e.g., "dhash-256").
+	Algorithm string `json:"algorithm"`
+
+	// The hamming distance up to which two logos are considered the same.
+	MaxDistance int `json:"max_distance"`
+
+	// The hashes of the listed token logos, sorted by the order index.
+	Hashes []LogoHash `json:"hashes"`
+}
+
+// LogoHash is the model for the perceptual hash of a token logo.
+type LogoHash struct {
+	// The uuid of the token.
+	TokenUuid string `json:"token_uuid"`
+
+	// The symbol of the token.
+	Symbol string `json:"symbol"`
+
+	// Whether the token is featured.
+	IsFeatured bool `json:"is_featured"`
+
+	// The hex encoded hash of the logo.png.
+	Hash string `json:"hash"`
+}
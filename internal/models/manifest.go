@@ -0,0 +1,33 @@
+package models
+
+// Manifest is the model for the build manifest (manifest.json).
+// it lists every published artifact, so the clients can check the manifest
+// and download only the artifacts whose hash changed.
+type Manifest struct {
+	// The version of the registry. it never decreases between the builds.
+	RegistryVersion int64 `json:"registry_version"`
+
+	// The time of the build in RFC3339 format (UTC).
+	BuildTime string `json:"build_time"`
+
+	// The git commit of the source the registry is built from.
+	SourceCommit string `json:"source_commit"`
+
+	// The published artifacts, sorted by the path.
+	Artifacts []ManifestArtifact `json:"artifacts"`
+}
+
+// ManifestArtifact is the model for a published artifact.
+type ManifestArtifact struct {
+	// The path of the artifact relative to the root of the registry (e.g., "tokens.json").
+	Path string `json:"path"`
+
+	// The hex encoded SHA-256 of the artifact content.
+	Sha256 string `json:"sha256"`
+
+	// The size of the artifact in bytes.
+	Size int64 `json:"size"`
+
+	// The content type of the artifact (e.g., "application/json", "image/png").
+	ContentType string `json:"content_type"`
+}
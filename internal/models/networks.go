@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // Network is the model for a network.
 type Network struct {
 	// The ID of the network.
@@ -24,10 +26,12 @@ type Network struct {
 	// Number of decimal places for the native token
 	Decimals int64 `json:"decimals"`
 
-	// PNG format icon URL for the network
+	// PNG format icon URL for the network.
+	// the build rewrites it to the CDN copy of the icon.png in the network folder.
 	IconPngUrl string `json:"icon_png_url"`
 
-	// SVG format icon URL for the network
+	// SVG format icon URL for the network.
+	// the build rewrites it to the CDN copy of the icon.svg in the network folder, if any.
 	IconSvgUrl string `json:"icon_svg_url"`
 
 	// Whether the network is a testnet.
@@ -44,6 +48,21 @@ type Network struct {
 
 	// The ID of the network on CoinMarketCap.
 	CoinMarketCapId int64 `json:"coin_marketcap_id"`
+
+	// The CAIP-2 chain id of the network (e.g., "eip155:1").
+	// leave empty, it is derived from the network type and the chain id, refer to CAIP2.
+	Caip2 string `json:"caip2,omitempty"`
+}
+
+// CanonicalAddress returns the canonical form of the address on the network,
+// the addresses of the ethereum-like networks are case-insensitive, so they are lowercased.
+// the other addresses are case-sensitive and only trimmed.
+func (n Network) CanonicalAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if n.NetworkType == NetworkType_NETWORK_TYPE_ETH_LIKE {
+		return strings.ToLower(address)
+	}
+	return address
 }
 
 // Explorer is the model for a network explorer.
@@ -97,3 +116,43 @@ const (
 	// TRON
 	Coin_TYPE_TRX Coin_Type = 195
 )
+
+// IsKnown reports whether the network type is one of the defined network types other than unspecified.
+func (t NetworkType) IsKnown() bool {
+	switch t {
+	case NetworkType_NETWORK_TYPE_ETH_LIKE,
+		NetworkType_NETWORK_TYPE_TRX,
+		NetworkType_NETWORK_TYPE_SOL,
+		NetworkType_NETWORK_TYPE_UTXO:
+		return true
+	default:
+		return false
+	}
+}
+
+// CoinTypes returns the coin types the networks of the type may have,
+// the ethereum-like networks use the coin type of ether, or of BNB for BNB Smart Chain.
+func (t NetworkType) CoinTypes() []Coin_Type {
+	switch t {
+	case NetworkType_NETWORK_TYPE_ETH_LIKE:
+		return []Coin_Type{Coin_TYPE_ETH, Coin_TYPE_BNB}
+	case NetworkType_NETWORK_TYPE_TRX:
+		return []Coin_Type{Coin_TYPE_TRX}
+	case NetworkType_NETWORK_TYPE_SOL:
+		return []Coin_Type{Coin_TYPE_SOL}
+	case NetworkType_NETWORK_TYPE_UTXO:
+		return []Coin_Type{Coin_TYPE_BTC}
+	default:
+		return nil
+	}
+}
+
+// IsKnown reports whether the coin type is one of the defined coin types.
+func (c Coin_Type) IsKnown() bool {
+	switch c {
+	case Coin_TYPE_BTC, Coin_TYPE_ETH, Coin_TYPE_BNB, Coin_TYPE_SOL, Coin_TYPE_TRX:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// OriginKind is the kind of the origin of a token address.
+type OriginKind string
+
+const (
+	// The address is a canonical issuance of the asset (e.g., USDC on Ethereum or on Solana).
+	OriginKind_CANONICAL OriginKind = "canonical"
+
+	// The address is a bridge representation of the source address on another network (e.g., USDC.e).
+	OriginKind_BRIDGED OriginKind = "bridged"
+
+	// The address wraps the source address, usually the native token of the same network (e.g., WETH).
+	OriginKind_WRAPPED OriginKind = "wrapped"
+)
+
+// OriginKinds is the list of the origin kinds.
+var OriginKinds = []OriginKind{OriginKind_CANONICAL, OriginKind_BRIDGED, OriginKind_WRAPPED}
+
+// bridgeIdRegex matches the bridge ids, lowercase words separated by dashes (e.g., "wormhole", "binance-peg").
+var bridgeIdRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Origin is the model for the origin of a token address.
+type Origin struct {
+	// The origin kind.
+	Kind OriginKind `json:"kind"`
+
+	// The id of the bridge (e.g., "wormhole"), required for the bridged addresses only.
+	Bridge string `json:"bridge,omitempty"`
+
+	// The address the bridged or the wrapped address represents, required for them only.
+	Source *AddressRef `json:"source,omitempty"`
+}
+
+// Clone returns a deep copy of the origin.
+func (o *Origin) Clone() *Origin {
+	if o == nil {
+		return nil
+	}
+	c := *o
+	if o.Source != nil {
+		source := *o.Source
+		c.Source = &source
+	}
+	return &c
+}
+
+// Validate validates the consistency of the origin fields:
+// - kind is one of OriginKinds.
+// - a canonical address has no bridge and no source.
+// - a bridged address has a bridge id and a source.
+// - a wrapped address has a source and no bridge.
+// it returns an error if any.
+func (o *Origin) Validate() error {
+	if o == nil {
+		return nil
+	}
+	switch o.Kind {
+	case OriginKind_CANONICAL:
+		if o.Bridge != "" || o.Source != nil {
+			return errors.New("canonical origin must not have a bridge or a source")
+		}
+	case OriginKind_BRIDGED:
+		if !bridgeIdRegex.MatchString(o.Bridge) {
+			return fmt.Errorf("bridged origin requires a bridge id of lowercase words separated by dashes, got: %q", o.Bridge)
+		}
+		if o.Source == nil {
+			return errors.New("bridged origin requires a source")
+		}
+	case OriginKind_WRAPPED:
+		if o.Bridge != "" {
+			return errors.New("wrapped origin must not have a bridge")
+		}
+		if o.Source == nil {
+			return errors.New("wrapped origin requires a source")
+		}
+	default:
+		return fmt.Errorf("unknown origin kind %q, expected one of %v", o.Kind, OriginKinds)
+	}
+	return nil
+}
+
+// AssetGraph is the model for the cross-chain asset graph (asset_graph.json).
+// the nodes are the token addresses with an origin or referenced as a source,
+// the edges point from the bridged and the wrapped addresses to their sources.
+type AssetGraph struct {
+	// The nodes sorted by the id.
+	Nodes []AssetNode `json:"nodes"`
+
+	// The edges sorted by the source and then by the target.
+	Edges []AssetEdge `json:"edges"`
+
+	// The nodes grouped by the asset, the nodes of the same token and the nodes connected by an edge
+	// are the same asset. sorted by the first member.
+	Assets []AssetGroup `json:"assets"`
+}
+
+// AssetNode is the model for a token address in the asset graph.
+type AssetNode struct {
+	// The CAIP-19 asset id of the address, the id of the node.
+	Id string `json:"id"`
+
+	// The ID of the network.
+	NetworkId int32 `json:"network_id"`
+
+	// The canonical address of the token on the network, refer to Network.CanonicalAddress.
+	Address string `json:"address"`
+
+	// The uuid of the token.
+	TokenUuid string `json:"token_uuid"`
+
+	// The symbol of the token.
+	Symbol string `json:"symbol"`
+
+	// The origin kind of the address, empty if the address declares none.
+	Origin OriginKind `json:"origin,omitempty"`
+}
+
+// AssetEdge is the model for an edge of the asset graph.
+type AssetEdge struct {
+	// The id of the bridged or the wrapped node.
+	From string `json:"from"`
+
+	// The id of the source node.
+	To string `json:"to"`
+
+	// The origin kind of the from node, bridged or wrapped.
+	Kind OriginKind `json:"kind"`
+
+	// The id of the bridge, for the bridged edges only.
+	Bridge string `json:"bridge,omitempty"`
+}
+
+// AssetGroup is the model for the addresses of the same asset in the asset graph.
+type AssetGroup struct {
+	// The ids of the root nodes, the nodes without a source (e.g., the canonical issuances), sorted.
+	Roots []string `json:"roots"`
+
+	// The ids of the nodes of the group, including the roots, sorted.
+	Members []string `json:"members"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// LivePrice is the model for the response of a live price url, refer to Token.LivePriceUrl.
+// the changes are percentages (e.g., 2.34 means 2.34%).
+// the optional fields are nil when the response omits them, they are omitted from the json as well.
+type LivePrice struct {
+	// The price in USD, required.
+	PriceUsd float64 `json:"price_usd"`
+
+	// The trading volume of the last 24 hours in USD, optional.
+	Volume24h *float64 `json:"volume_24h,omitempty"`
+
+	// The change of the trading volume in the last 24 hours, optional.
+	VolumeChange24h *float64 `json:"volume_change_24h,omitempty"`
+
+	// The change of the price in the last hour, optional.
+	PercentChange1h *float64 `json:"percent_change_1h,omitempty"`
+
+	// The change of the price in the last 24 hours, optional.
+	PercentChange24h *float64 `json:"percent_change_24h,omitempty"`
+
+	// The change of the price in the last 7 days, optional.
+	PercentChange7d *float64 `json:"percent_change_7d,omitempty"`
+
+	// The change of the price in the last 30 days, optional.
+	PercentChange30d *float64 `json:"percent_change_30d,omitempty"`
+
+	// The change of the price in the last 90 days, optional.
+	PercentChange90d *float64 `json:"percent_change_90d,omitempty"`
+
+	// The time the price was last updated (RFC 3339), optional.
+	// when it is omitted the Last-Modified header of the response is used instead.
+	LastUpdated *time.Time `json:"last_updated,omitempty"`
+}
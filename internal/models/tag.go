@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// TagCategory is the category of a tag.
+type TagCategory string
+
+const (
+	// The tag describes what the asset is (e.g., stablecoin, rwa, meme).
+	TagCategory_ASSET TagCategory = "asset"
+
+	// The tag describes the sector the token is used in (e.g., defi, gaming).
+	TagCategory_SECTOR TagCategory = "sector"
+
+	// The tag describes what the token is used for (e.g., governance).
+	TagCategory_UTILITY TagCategory = "utility"
+)
+
+// TagCategories is the list of the tag categories.
+var TagCategories = []TagCategory{TagCategory_ASSET, TagCategory_SECTOR, TagCategory_UTILITY}
+
+// StablecoinTag is the id of the tag the tokens with is_stable_token must have, and only them.
+const StablecoinTag = "stablecoin"
+
+// tagIdRegex matches the tag ids, lowercase words separated by dashes (e.g., "stablecoin", "liquid-staking").
+var tagIdRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Tags is the model for the tag registry (tags/tags.json), the tokens can only have the tags defined in it.
+type Tags struct {
+	// The tag definitions, sorted by the id.
+	Tags []Tag `json:"tags"`
+}
+
+// Tag is the model for a tag definition.
+type Tag struct {
+	// The id of the tag, the value used in the tags of the tokens.
+	Id string `json:"id"`
+
+	// The display name of the tag (e.g., "Stablecoin").
+	Name string `json:"name"`
+
+	// A brief description of the tag.
+	Description string `json:"description"`
+
+	// The category of the tag.
+	Category TagCategory `json:"category"`
+}
+
+// Validate validates the tag definition:
+// - id is lowercase words separated by dashes.
+// - name and description are present.
+// - category is one of TagCategories.
+// it returns an error if any.
+func (t Tag) Validate() error {
+	if !tagIdRegex.MatchString(t.Id) {
+		return fmt.Errorf("id must be lowercase words separated by dashes, got: %q", t.Id)
+	}
+	if t.Name == "" {
+		return errors.New("name is required")
+	}
+	if t.Description == "" {
+		return errors.New("description is required")
+	}
+	if !slices.Contains(TagCategories, t.Category) {
+		return fmt.Errorf("unknown category %q, expected one of %v", t.Category, TagCategories)
+	}
+	return nil
+}
+
+// TagIndex is the model for the tokens with a tag (tags/:tag.json).
+type TagIndex struct {
+	// The tag definition.
+	Tag Tag `json:"tag"`
+
+	// The tokens with the tag, sorted by the order index.
+	Tokens []TaggedToken `json:"tokens"`
+}
+
+// TaggedToken is the model for a token in the tag index.
+type TaggedToken struct {
+	// The uuid of the token.
+	TokenUuid string `json:"token_uuid"`
+
+	// The symbol of the token.
+	Symbol string `json:"symbol"`
+
+	// The name of the token.
+	Name string `json:"name"`
+}
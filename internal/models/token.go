@@ -20,10 +20,12 @@ type Token struct {
 	// ID of the token that is wrapped by this token (if applicable)
 	WrappedTokenUuid string `json:"wrapped_token_uuid"`
 
-	// URL of the logo of the token in the form of png. must be 64x64.
+	// URL of the logo of the token in the form of png (64x64).
+	// the build rewrites it to the CDN copy of the logo.png in the token folder.
 	LogoPngUrl string `json:"logo_png_url"`
 
 	// URL of the logo of the token in the form of svg.
+	// the build rewrites it to the CDN copy of the logo.svg in the token folder, empty without one.
 	LogoSvgUrl string `json:"logo_svg_url"`
 
 	// Description of the token.
@@ -63,12 +65,18 @@ type Token struct {
 	//  "percent_change_7d": 2.34, // 2.34%
 	//  "percent_change_30d": 2.34, // 2.34%
 	//  "percent_change_90d": 2.34, // 2.34%
+	//  "last_updated": "2025-01-01T00:00:00Z" // optional, RFC 3339
 	// }
+	// price_usd is required, refer to LivePrice.
 	LivePriceUrl string `json:"live_price_url"`
 
 	// the tags of the token.
 	Tags []string `json:"tags"`
 
+	// The uuids of the tokens that are the same asset listed separately (e.g., the same asset on another chain).
+	// the tokens must list each other, then their symbols and names are allowed to look the same.
+	SameAssetAs []string `json:"same_asset_as,omitempty"`
+
 	// Whether the token is a scam.
 	IsScam bool `json:"is_scam"`
 
@@ -80,6 +88,24 @@ type Token struct {
 	// Whether the token's price is tracking by the Matrix Wallet or not.
 	IsTracking bool `json:"is_tracking"`
 
+	// The lifecycle of the token, omit while the token is active.
+	// refer to Lifecycle, the lifecycle of an address overrides it.
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+
 	// the addresses of the token on the networks.
 	Addresses []TokenAddress `json:"addresses"`
+
+	// The CDN URLs of the logo variants generated by the build.
+	// leave empty, it is ignored in the meta.json.
+	Logos *LogoSet `json:"logos,omitempty"`
+}
+
+// LogoSet is the model for the logo variants of a token.
+// the key is the size of the square logo in pixels, the value is the URL.
+type LogoSet struct {
+	// The PNG variants of the logo.
+	Png map[int]string `json:"png"`
+
+	// The lossless WebP variants of the logo.
+	Webp map[int]string `json:"webp"`
 }
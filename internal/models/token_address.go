@@ -42,8 +42,26 @@ type TokenAddress struct {
 	Symbol string `json:"symbol"`
 
 	// The URL of the logo of the token. PNG is required
+	// the build rewrites it to the logo_png_url of the token.
 	LogoPngUrl string `json:"logo_png_url"`
 
 	// The URL of the logo of the token in the form of svg.
+	// the build rewrites it to the logo_svg_url of the token.
 	LogoSvgUrl string `json:"logo_svg_url"`
+
+	// The lifecycle of the token address (e.g., the old contract of a migrated token),
+	// omit to inherit the lifecycle of the token.
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+
+	// The origin of the token address (canonical, bridged or wrapped), omit if unknown.
+	// refer to Origin.
+	Origin *Origin `json:"origin,omitempty"`
+
+	// The URL of the token page on the network explorer, the native tokens have none.
+	// leave empty, the build fills it in from the network explorer token template.
+	ExplorerUrl string `json:"explorer_url,omitempty"`
+
+	// The CAIP-19 asset id of the token address (e.g., "eip155:1/erc20:0xdac1...").
+	// leave empty, the build fills it in, refer to Network.CAIP19.
+	Caip19 string `json:"caip19,omitempty"`
 }
@@ -0,0 +1,85 @@
+package onchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// The selectors of the ERC-20 metadata methods.
+const (
+	selectorName     = "0x06fdde03"
+	selectorSymbol   = "0x95d89b41"
+	selectorDecimals = "0x313ce567"
+)
+
+// The EIP-1967 storage slots of the proxies.
+const (
+	implementationSlot = "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"
+	beaconSlot         = "0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"
+)
+
+// decodeHex decodes the 0x prefixed hex data of the JSON-RPC results.
+func decodeHex(s string) ([]byte, error) {
+	data, ok := strings.CutPrefix(s, "0x")
+	if !ok {
+		return nil, fmt.Errorf("hex data must start with 0x, got %q", s)
+	}
+	if len(data)%2 == 1 {
+		data = "0" + data
+	}
+	return hex.DecodeString(data)
+}
+
+// decodeUint8 decodes the ABI encoded uint of the decimals() result, which must fit into a uint8.
+func decodeUint8(data []byte) (uint8, error) {
+	if len(data) < 32 {
+		return 0, fmt.Errorf("expected a 32 byte word, got %d bytes", len(data))
+	}
+	value := new(big.Int).SetBytes(data[:32])
+	if !value.IsUint64() || value.Uint64() > 255 {
+		return 0, fmt.Errorf("value %s does not fit into a uint8", value)
+	}
+	return uint8(value.Uint64()), nil
+}
+
+// decodeString decodes the ABI encoded string of the name() and symbol() results,
+// the older contracts (e.g., MKR) return a bytes32 padded with zeros instead.
+func decodeString(data []byte) (string, error) {
+	if len(data) == 32 {
+		s := string(bytes.TrimRight(data, "\x00"))
+		if !utf8.ValidString(s) {
+			return "", errors.New("bytes32 is not valid utf-8")
+		}
+		return s, nil
+	}
+	if len(data) < 64 {
+		return "", fmt.Errorf("expected an ABI encoded string, got %d bytes", len(data))
+	}
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-32) {
+		return "", fmt.Errorf("string offset %s is out of range", offset)
+	}
+	start := offset.Uint64() + 32
+	length := new(big.Int).SetBytes(data[start-32 : start])
+	if !length.IsUint64() || length.Uint64() > uint64(len(data))-start {
+		return "", fmt.Errorf("string length %s is out of range", length)
+	}
+	s := string(data[start : start+length.Uint64()])
+	if !utf8.ValidString(s) {
+		return "", errors.New("string is not valid utf-8")
+	}
+	return s, nil
+}
+
+// decodeAddress decodes the address stored in a storage slot, empty if the slot is zero.
+func decodeAddress(data []byte) string {
+	if len(data) < 20 || bytes.Count(data, []byte{0}) == len(data) {
+		return ""
+	}
+	return "0x" + hex.EncodeToString(data[len(data)-20:])
+}
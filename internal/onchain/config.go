@@ -0,0 +1,41 @@
+package onchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// LoadConfig loads the RPC urls by the network id from the JSON file, e.g.:
+//
+//	{"2": "https://eth.example/${ETH_RPC_KEY}", "3": "https://bsc-dataseed.example"}
+//
+// the environment variables in the urls are expanded, so that the API keys are not kept in the file.
+// it returns an error if any.
+func LoadConfig(path string) (map[int64]string, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]string
+	if err := json.Unmarshal(bytes, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	rpcUrls := make(map[int64]string, len(config))
+	for key, rawUrl := range config {
+		networkId, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || networkId <= 0 {
+			return nil, fmt.Errorf("%s: invalid network id %q", path, key)
+		}
+		rpcUrl := os.ExpandEnv(rawUrl)
+		u, err := url.Parse(rpcUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			// the expanded url is not printed, it may contain an API key.
+			return nil, fmt.Errorf("%s: network %d: rpc url %q must be an http(s) URL", path, networkId, rawUrl)
+		}
+		rpcUrls[networkId] = rpcUrl
+	}
+	return rpcUrls, nil
+}
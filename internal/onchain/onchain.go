@@ -0,0 +1,275 @@
+// Package onchain verifies the token addresses of the ethereum-like networks
+// against their contracts over JSON-RPC.
+//
+// A Verifier reads the contract of an address through the RPC url of its
+// network: the bytecode, decimals(), symbol() and name(), and the EIP-1967
+// implementation and beacon slots of the proxies. The differences to the
+// token address are reported as Mismatch errors:
+//
+//	verifier := onchain.New(map[int64]string{2: "https://ethereum-rpc.example"})
+//	errs := verifier.Verify(ctx, network, address)
+package onchain
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// The defaults of the verifier.
+const (
+	DefaultTimeout = 10 * time.Second
+	DefaultMaxSize = 1 << 20
+)
+
+// Metadata is the metadata of a token contract read over JSON-RPC.
+type Metadata struct {
+	// Whether there is a contract at the address, the other fields are empty without one.
+	HasCode bool
+
+	// The result of decimals().
+	Decimals uint8
+
+	// The result of symbol().
+	Symbol string
+
+	// The result of name().
+	Name string
+
+	// The address in the EIP-1967 implementation slot, empty if the slot is zero.
+	Implementation string
+
+	// The address in the EIP-1967 beacon slot, empty if the slot is zero.
+	Beacon string
+}
+
+// Upgradeable reports whether the contract is an EIP-1967 proxy.
+func (m *Metadata) Upgradeable() bool {
+	return m.Implementation != "" || m.Beacon != ""
+}
+
+// Mismatch is a field of the token address that differs from its contract.
+type Mismatch struct {
+	// The json name of the field (e.g., "decimals").
+	Field string
+
+	// The value in the registry.
+	Listed string
+
+	// The value read from the contract.
+	OnChain string
+}
+
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("%s is %s in the registry, but %s on-chain", m.Field, m.Listed, m.OnChain)
+}
+
+// Verifier verifies the token addresses against their contracts, it is safe for concurrent use.
+type Verifier struct {
+	rpcUrls    map[int64]string
+	httpClient *http.Client
+	timeout    time.Duration
+	maxSize    int64
+
+	mu sync.Mutex
+	// the clients by the network id, created on the first use.
+	clients map[int64]*rpcClient
+	// the verified chain ids by the network id, an error if the RPC url serves another chain.
+	chains map[int64]error
+}
+
+// Option configures the verifier.
+type Option func(*Verifier)
+
+// WithHTTPClient sets the HTTP client the RPC urls are called with.
+// default is http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(v *Verifier) {
+		v.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the time limit of the verification of an address.
+// default is DefaultTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(v *Verifier) {
+		v.timeout = timeout
+	}
+}
+
+// WithMaxSize sets the size limit of a JSON-RPC response in bytes.
+// default is DefaultMaxSize.
+func WithMaxSize(maxSize int64) Option {
+	return func(v *Verifier) {
+		v.maxSize = maxSize
+	}
+}
+
+// New returns a verifier of the networks with an RPC url, the key is the network id.
+func New(rpcUrls map[int64]string, opts ...Option) *Verifier {
+	v := &Verifier{
+		rpcUrls:    rpcUrls,
+		httpClient: http.DefaultClient,
+		timeout:    DefaultTimeout,
+		maxSize:    DefaultMaxSize,
+		clients:    make(map[int64]*rpcClient),
+		chains:     make(map[int64]error),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Supports reports whether the network is ethereum-like and has an RPC url.
+func (v *Verifier) Supports(network models.Network) bool {
+	_, ok := v.rpcUrls[network.Id]
+	return ok && network.NetworkType == models.NetworkType_NETWORK_TYPE_ETH_LIKE
+}
+
+// client returns the client of the network.
+func (v *Verifier) client(networkId int64) *rpcClient {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	c, ok := v.clients[networkId]
+	if !ok {
+		c = &rpcClient{url: v.rpcUrls[networkId], httpClient: v.httpClient, maxSize: v.maxSize}
+		v.clients[networkId] = c
+	}
+	return c
+}
+
+// checkChain verifies once per network that the RPC url serves the chain of the network.
+func (v *Verifier) checkChain(ctx context.Context, network models.Network) error {
+	v.mu.Lock()
+	err, ok := v.chains[network.Id]
+	v.mu.Unlock()
+	if ok {
+		return err
+	}
+	var result string
+	// the failed requests are not remembered, they are retried on the next address.
+	if err := v.client(network.Id).call(ctx, "eth_chainId", &result); err != nil {
+		return err
+	}
+	chainId, err := strconv.ParseInt(strings.TrimPrefix(result, "0x"), 16, 64)
+	if err != nil {
+		err = fmt.Errorf("eth_chainId: invalid chain id %q", result)
+	} else if chainId != network.ChainId {
+		err = fmt.Errorf("rpc url of network %d serves chain %d, expected %d", network.Id, chainId, network.ChainId)
+	}
+	v.mu.Lock()
+	v.chains[network.Id] = err
+	v.mu.Unlock()
+	return err
+}
+
+// Fetch reads the metadata of the contract at the address on the network.
+// it returns an error if the network is not supported or a call fails.
+func (v *Verifier) Fetch(ctx context.Context, network models.Network, address string) (*Metadata, error) {
+	if !v.Supports(network) {
+		return nil, fmt.Errorf("network %d is not ethereum-like or has no rpc url", network.Id)
+	}
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+	if err := v.checkChain(ctx, network); err != nil {
+		return nil, err
+	}
+	c := v.client(network.Id)
+
+	var code string
+	if err := c.call(ctx, "eth_getCode", &code, address, "latest"); err != nil {
+		return nil, err
+	}
+	if code == "0x" || code == "" {
+		return &Metadata{}, nil
+	}
+	m := &Metadata{HasCode: true}
+
+	data, err := v.ethCall(ctx, c, address, selectorDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("decimals(): %w", err)
+	}
+	if m.Decimals, err = decodeUint8(data); err != nil {
+		return nil, fmt.Errorf("decimals(): %w", err)
+	}
+	for _, call := range []struct {
+		name     string
+		selector string
+		target   *string
+	}{
+		{"symbol()", selectorSymbol, &m.Symbol},
+		{"name()", selectorName, &m.Name},
+	} {
+		data, err := v.ethCall(ctx, c, address, call.selector)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", call.name, err)
+		}
+		if *call.target, err = decodeString(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", call.name, err)
+		}
+	}
+	for _, slot := range []struct {
+		slot   string
+		target *string
+	}{
+		{implementationSlot, &m.Implementation},
+		{beaconSlot, &m.Beacon},
+	} {
+		var result string
+		if err := c.call(ctx, "eth_getStorageAt", &result, address, slot.slot, "latest"); err != nil {
+			return nil, err
+		}
+		data, err := decodeHex(result)
+		if err != nil {
+			return nil, fmt.Errorf("eth_getStorageAt: %w", err)
+		}
+		*slot.target = decodeAddress(data)
+	}
+	return m, nil
+}
+
+// ethCall calls the method of the contract without arguments and returns the result.
+func (v *Verifier) ethCall(ctx context.Context, c *rpcClient, address, selector string) ([]byte, error) {
+	var result string
+	err := c.call(ctx, "eth_call", &result, map[string]string{"to": address, "data": selector}, "latest")
+	if err != nil {
+		return nil, err
+	}
+	return decodeHex(result)
+}
+
+// Verify reads the contract of the token address and compares it with the address:
+// - there is a contract at the address.
+// - decimals, symbol and name match the contract.
+// - upgradeable matches whether the contract is an EIP-1967 proxy.
+// it returns the mismatches, as *Mismatch errors, or the error of the fetch if any.
+func (v *Verifier) Verify(ctx context.Context, network models.Network, address models.TokenAddress) []error {
+	m, err := v.Fetch(ctx, network, address.Address)
+	if err != nil {
+		return []error{err}
+	}
+	if !m.HasCode {
+		return []error{fmt.Errorf("no contract is deployed at %s", address.Address)}
+	}
+	var errs []error
+	if uint32(m.Decimals) != address.Decimals {
+		errs = append(errs, &Mismatch{Field: "decimals", Listed: strconv.Itoa(int(address.Decimals)), OnChain: strconv.Itoa(int(m.Decimals))})
+	}
+	if m.Symbol != address.Symbol {
+		errs = append(errs, &Mismatch{Field: "symbol", Listed: strconv.Quote(address.Symbol), OnChain: strconv.Quote(m.Symbol)})
+	}
+	if m.Name != address.Name {
+		errs = append(errs, &Mismatch{Field: "name", Listed: strconv.Quote(address.Name), OnChain: strconv.Quote(m.Name)})
+	}
+	if m.Upgradeable() != address.Upgradeable {
+		errs = append(errs, &Mismatch{Field: "upgradeable", Listed: strconv.FormatBool(address.Upgradeable), OnChain: strconv.FormatBool(m.Upgradeable())})
+	}
+	return errs
+}
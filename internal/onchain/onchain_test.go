@@ -0,0 +1,295 @@
+package onchain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+const (
+	testAddress        = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	testImplementation = "0x00000000000000000000000000000000000000aa"
+)
+
+var testNetwork = models.Network{Id: 2, NetworkType: models.NetworkType_NETWORK_TYPE_ETH_LIKE, ChainId: 1}
+
+// fakeChain is a fake JSON-RPC endpoint serving a single contract.
+type fakeChain struct {
+	mu sync.Mutex
+	// the results by the method, the eth_call results by the selector and the eth_getStorageAt results by the slot.
+	results map[string]any
+	// the errors by the method or the selector.
+	errors map[string]*rpcError
+	// the number of the calls by the method.
+	calls map[string]int
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{
+		results: map[string]any{
+			"eth_chainId":      "0x1",
+			"eth_getCode":      "0x6080604052",
+			selectorDecimals:   encodeWord(big.NewInt(6)),
+			selectorSymbol:     encodeString("USDT"),
+			selectorName:       encodeString("Tether USD"),
+			implementationSlot: encodeWord(new(big.Int)),
+			beaconSlot:         encodeWord(new(big.Int)),
+		},
+		errors: map[string]*rpcError{},
+		calls:  map[string]int{},
+	}
+}
+
+func (f *fakeChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Id     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key := req.Method
+	switch req.Method {
+	case "eth_call":
+		var call struct{ To, Data string }
+		_ = json.Unmarshal(req.Params[0], &call)
+		key = call.Data
+	case "eth_getStorageAt":
+		_ = json.Unmarshal(req.Params[1], &key)
+	}
+
+	f.mu.Lock()
+	f.calls[req.Method]++
+	result, rpcErr := f.results[key], f.errors[key]
+	f.mu.Unlock()
+	response := map[string]any{"jsonrpc": "2.0", "id": req.Id}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (f *fakeChain) set(key string, result any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[key] = result
+}
+
+// encodeWord encodes the value as the hex data of a 32 byte word.
+func encodeWord(value *big.Int) string {
+	return "0x" + hex.EncodeToString(value.FillBytes(make([]byte, 32)))
+}
+
+// encodeString encodes the string as the hex data of an ABI encoded string.
+func encodeString(s string) string {
+	data := make([]byte, 64+(len(s)+31)/32*32)
+	big.NewInt(32).FillBytes(data[:32])
+	big.NewInt(int64(len(s))).FillBytes(data[32:64])
+	copy(data[64:], s)
+	return "0x" + hex.EncodeToString(data)
+}
+
+// encodeBytes32 encodes the string as the hex data of a bytes32 padded with zeros.
+func encodeBytes32(s string) string {
+	data := make([]byte, 32)
+	copy(data, s)
+	return "0x" + hex.EncodeToString(data)
+}
+
+func newTestVerifier(t *testing.T, chain *fakeChain) *Verifier {
+	t.Helper()
+	server := httptest.NewServer(chain)
+	t.Cleanup(server.Close)
+	return New(map[int64]string{testNetwork.Id: server.URL}, WithHTTPClient(server.Client()))
+}
+
+func testTokenAddress() models.TokenAddress {
+	return models.TokenAddress{NetworkId: 2, Address: testAddress, Decimals: 6, Symbol: "USDT", Name: "Tether USD", TokenType: "ERC20"}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(chain *fakeChain, address *models.TokenAddress)
+		want   []string
+	}{
+		{"matching contract", func(chain *fakeChain, address *models.TokenAddress) {}, nil},
+		{"no contract", func(chain *fakeChain, address *models.TokenAddress) {
+			chain.set("eth_getCode", "0x")
+		}, []string{"no contract is deployed at " + testAddress}},
+		{"bytes32 symbol and name", func(chain *fakeChain, address *models.TokenAddress) {
+			chain.set(selectorSymbol, encodeBytes32("MKR"))
+			chain.set(selectorName, encodeBytes32("Maker"))
+			address.Symbol, address.Name = "MKR", "Maker"
+		}, nil},
+		{"decimals mismatch", func(chain *fakeChain, address *models.TokenAddress) {
+			chain.set(selectorDecimals, encodeWord(big.NewInt(18)))
+		}, []string{"decimals is 6 in the registry, but 18 on-chain"}},
+		{"symbol and name mismatch", func(chain *fakeChain, address *models.TokenAddress) {
+			address.Symbol, address.Name = "USDT0", "Tether"
+		}, []string{`symbol is "USDT0" in the registry, but "USDT" on-chain`, `name is "Tether" in the registry, but "Tether USD" on-chain`}},
+		{"implementation slot", func(chain *fakeChain, address *models.TokenAddress) {
+			implementation, _ := new(big.Int).SetString(strings.TrimPrefix(testImplementation, "0x"), 16)
+			chain.set(implementationSlot, encodeWord(implementation))
+		}, []string{"upgradeable is false in the registry, but true on-chain"}},
+		{"beacon slot", func(chain *fakeChain, address *models.TokenAddress) {
+			chain.set(beaconSlot, encodeWord(big.NewInt(0xbb)))
+			address.Upgradeable = true
+		}, nil},
+		{"not a proxy", func(chain *fakeChain, address *models.TokenAddress) {
+			address.Upgradeable = true
+		}, []string{"upgradeable is true in the registry, but false on-chain"}},
+		{"decimals out of range", func(chain *fakeChain, address *models.TokenAddress) {
+			chain.set(selectorDecimals, encodeWord(big.NewInt(256)))
+		}, []string{"decimals(): value 256 does not fit into a uint8"}},
+		{"decimals too short", func(chain *fakeChain, address *models.TokenAddress) {
+			chain.set(selectorDecimals, "0x06")
+		}, []string{"decimals(): expected a 32 byte word, got 1 bytes"}},
+		{"rpc error", func(chain *fakeChain, address *models.TokenAddress) {
+			chain.errors[selectorSymbol] = &rpcError{Code: 3, Message: "execution reverted"}
+		}, []string{"symbol(): eth_call: rpc error 3: execution reverted"}},
+		{"invalid hex", func(chain *fakeChain, address *models.TokenAddress) {
+			chain.set(selectorName, "06fdde03")
+		}, []string{"name(): hex data must start with 0x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newFakeChain()
+			address := testTokenAddress()
+			tt.modify(chain, &address)
+			errs := newTestVerifier(t, chain).Verify(context.Background(), testNetwork, address)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Verify() = %v, want %d errors", errs, len(tt.want))
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Error(), tt.want[i]) {
+					t.Errorf("Verify()[%d] = %q, want it to contain %q", i, err, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyMismatchType(t *testing.T) {
+	chain := newFakeChain()
+	chain.set(selectorDecimals, encodeWord(big.NewInt(18)))
+	errs := newTestVerifier(t, chain).Verify(context.Background(), testNetwork, testTokenAddress())
+	var mismatch *Mismatch
+	if len(errs) != 1 || !errors.As(errs[0], &mismatch) || mismatch.Field != "decimals" || mismatch.OnChain != "18" {
+		t.Errorf("Verify() = %v, want a *Mismatch of the decimals", errs)
+	}
+}
+
+func TestFetchChainId(t *testing.T) {
+	chain := newFakeChain()
+	chain.set("eth_chainId", "0x38")
+	v := newTestVerifier(t, chain)
+	for range 2 {
+		_, err := v.Fetch(context.Background(), testNetwork, testAddress)
+		if err == nil || !strings.Contains(err.Error(), "serves chain 56, expected 1") {
+			t.Errorf("Fetch() error = %v, want a chain id mismatch", err)
+		}
+	}
+	if chain.calls["eth_chainId"] != 1 {
+		t.Errorf("eth_chainId was called %d times, want 1, the mismatch is remembered", chain.calls["eth_chainId"])
+	}
+	if chain.calls["eth_getCode"] != 0 {
+		t.Errorf("eth_getCode was called on the wrong chain")
+	}
+
+	// the failed requests are retried on the next address.
+	chain = newFakeChain()
+	chain.errors["eth_chainId"] = &rpcError{Code: -32603, Message: "internal error"}
+	v = newTestVerifier(t, chain)
+	if _, err := v.Fetch(context.Background(), testNetwork, testAddress); err == nil || !strings.Contains(err.Error(), "rpc error -32603") {
+		t.Errorf("Fetch() error = %v, want the rpc error", err)
+	}
+	delete(chain.errors, "eth_chainId")
+	if _, err := v.Fetch(context.Background(), testNetwork, testAddress); err != nil {
+		t.Errorf("Fetch() after a failed eth_chainId error = %v, want it retried", err)
+	}
+	if chain.calls["eth_chainId"] != 2 {
+		t.Errorf("eth_chainId was called %d times, want 2", chain.calls["eth_chainId"])
+	}
+}
+
+func TestFetchUnsupportedNetwork(t *testing.T) {
+	v := New(map[int64]string{2: "http://127.0.0.1:0"})
+	for _, network := range []models.Network{
+		{Id: 3, NetworkType: models.NetworkType_NETWORK_TYPE_ETH_LIKE, ChainId: 56},
+		{Id: 2, NetworkType: models.NetworkType_NETWORK_TYPE_SOL},
+	} {
+		if v.Supports(network) {
+			t.Errorf("Supports(%d) = true, want false", network.Id)
+		}
+		if _, err := v.Fetch(context.Background(), network, testAddress); err == nil {
+			t.Errorf("Fetch() on network %d succeeded", network.Id)
+		}
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	word := func(n int64) []byte { return big.NewInt(n).FillBytes(make([]byte, 32)) }
+	concat := func(parts ...[]byte) []byte {
+		var data []byte
+		for _, part := range parts {
+			data = append(data, part...)
+		}
+		return data
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr string
+	}{
+		{"abi string", concat(word(32), word(4), []byte("USDT"), make([]byte, 28)), "USDT", ""},
+		{"empty abi string", concat(word(32), word(0)), "", ""},
+		{"bytes32", append([]byte("MKR"), make([]byte, 29)...), "MKR", ""},
+		{"empty", nil, "", "expected an ABI encoded string, got 0 bytes"},
+		{"too short", make([]byte, 40), "", "expected an ABI encoded string, got 40 bytes"},
+		{"offset out of range", concat(word(64), word(4)), "", "string offset 64 is out of range"},
+		{"huge offset", concat(append([]byte{1}, make([]byte, 31)...), word(4)), "", "is out of range"},
+		{"length out of range", concat(word(32), word(5), []byte("USDT")), "", "string length 5 is out of range"},
+		{"huge length", concat(word(32), append([]byte{1}, make([]byte, 31)...)), "", "is out of range"},
+		{"invalid utf-8", concat(word(32), word(2), []byte{0xff, 0xfe}, make([]byte, 30)), "", "not valid utf-8"},
+		{"invalid utf-8 bytes32", append([]byte{0xff}, make([]byte, 31)...), "", "not valid utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeString(tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("decodeString() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("decodeString() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeAddress(t *testing.T) {
+	implementation, _ := hex.DecodeString(strings.Repeat("00", 12) + strings.TrimPrefix(testImplementation, "0x"))
+	if got := decodeAddress(implementation); got != testImplementation {
+		t.Errorf("decodeAddress() = %q, want %q", got, testImplementation)
+	}
+	if got := decodeAddress(make([]byte, 32)); got != "" {
+		t.Errorf("decodeAddress() of a zero slot = %q, want empty", got)
+	}
+}
@@ -0,0 +1,271 @@
+// Package pricefeed checks the live price urls of the tokens against the
+// contract documented on models.Token.LivePriceUrl.
+//
+// A Checker fetches a url through its http.Client, with a timeout and a size
+// limit, and reports the problems of the response: a failed request, a
+// response that is not a JSON object, the fields of the wrong type or out of
+// range, and the prices older than the max age:
+//
+//	checker := pricefeed.New(pricefeed.WithMaxAge(time.Hour))
+//	price, errs := checker.Check(ctx, token.LivePriceUrl)
+//
+// The staleness problems wrap ErrStale or ErrUnknownAge, so that callers can
+// report them with a lower severity.
+package pricefeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// The defaults of the checker.
+const (
+	DefaultTimeout = 10 * time.Second
+	DefaultMaxSize = 64 << 10
+	DefaultMaxAge  = time.Hour
+)
+
+// maxClockSkew is how far in the future last_updated may be before it is reported.
+const maxClockSkew = 5 * time.Minute
+
+var (
+	// ErrStale is wrapped by the problems of the prices older than the max age.
+	ErrStale = errors.New("stale price")
+
+	// ErrUnknownAge is wrapped by the problem of a response without last_updated and Last-Modified.
+	ErrUnknownAge = errors.New("unknown price age")
+)
+
+// Checker checks the live price urls, it is safe for concurrent use.
+type Checker struct {
+	httpClient *http.Client
+	timeout    time.Duration
+	maxSize    int64
+	maxAge     time.Duration
+	now        func() time.Time
+}
+
+// Option configures the checker.
+type Option func(*Checker)
+
+// WithHTTPClient sets the HTTP client the urls are fetched with.
+// default is http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Checker) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the time limit of a check, including reading the response.
+// default is DefaultTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Checker) {
+		c.timeout = timeout
+	}
+}
+
+// WithMaxSize sets the size limit of a response in bytes.
+// default is DefaultMaxSize.
+func WithMaxSize(maxSize int64) Option {
+	return func(c *Checker) {
+		c.maxSize = maxSize
+	}
+}
+
+// WithMaxAge sets the age above which a price is reported as stale.
+// default is DefaultMaxAge.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(c *Checker) {
+		c.maxAge = maxAge
+	}
+}
+
+// WithClock sets the function the age of the prices is measured against.
+// default is time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(c *Checker) {
+		c.now = now
+	}
+}
+
+// New returns a checker with the options.
+func New(opts ...Option) *Checker {
+	c := &Checker{
+		httpClient: http.DefaultClient,
+		timeout:    DefaultTimeout,
+		maxSize:    DefaultMaxSize,
+		maxAge:     DefaultMaxAge,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Check fetches the url and validates the response against the contract.
+// it returns the price, nil if the response could not be fetched or decoded, and the problems if any.
+// the last_updated of the price falls back to the Last-Modified header of the response.
+func (c *Checker) Check(ctx context.Context, url string) (*models.LivePrice, []error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	body, lastModified, err := c.fetch(ctx, url)
+	if err != nil {
+		return nil, []error{err}
+	}
+	price, errs := Parse(body)
+	if price == nil {
+		return nil, errs
+	}
+
+	// the price is normalized to carry its update time, from the Last-Modified header if needed.
+	if price.LastUpdated == nil && !lastModified.IsZero() {
+		price.LastUpdated = &lastModified
+	}
+	var updated time.Time
+	if price.LastUpdated != nil {
+		updated = *price.LastUpdated
+	}
+	now := c.now()
+	switch {
+	case updated.IsZero():
+		errs = append(errs, fmt.Errorf("%w: the response has no last_updated and no Last-Modified header", ErrUnknownAge))
+	case updated.After(now.Add(maxClockSkew)):
+		errs = append(errs, fmt.Errorf("last_updated %s is in the future", updated.Format(time.RFC3339)))
+	case now.Sub(updated) > c.maxAge:
+		errs = append(errs, fmt.Errorf("%w: last updated %s ago, the limit is %s",
+			ErrStale, now.Sub(updated).Truncate(time.Second), c.maxAge))
+	}
+	return price, errs
+}
+
+// fetch returns the body of the response and its Last-Modified time, zero if it has none.
+func (c *Checker) fetch(ctx context.Context, url string) ([]byte, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid url: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxSize+1))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read the response: %w", err)
+	}
+	if int64(len(body)) > c.maxSize {
+		return nil, time.Time{}, fmt.Errorf("response is larger than %d bytes", c.maxSize)
+	}
+	// an invalid Last-Modified header is treated as missing.
+	lastModified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return body, lastModified, nil
+}
+
+// numberFields are the optional number fields of the contract, price_usd is checked on its own.
+var numberFields = []string{
+	"volume_24h",
+	"volume_change_24h",
+	"percent_change_1h",
+	"percent_change_24h",
+	"percent_change_7d",
+	"percent_change_30d",
+	"percent_change_90d",
+}
+
+// Parse validates the response body against the contract:
+// - the body is a JSON object.
+// - price_usd is a positive number.
+// - the other fields, if present, are numbers, volume_24h is not negative.
+// - last_updated, if present, is an RFC 3339 time.
+// the unknown fields are ignored.
+// it returns the price, nil if the body is not a JSON object or has a problem, and the problems if any.
+func Parse(body []byte) (*models.LivePrice, []error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return nil, []error{errors.New("response must be a JSON object")}
+	}
+
+	var errs []error
+	if raw, ok := fields["price_usd"]; !ok {
+		errs = append(errs, errors.New("price_usd is required"))
+	} else if price, err := parseNumber("price_usd", raw); err != nil {
+		errs = append(errs, err)
+	} else if price <= 0 {
+		errs = append(errs, fmt.Errorf("price_usd must be positive, got %v", price))
+	}
+	for _, field := range numberFields {
+		raw, ok := fields[field]
+		if !ok {
+			continue
+		}
+		value, err := parseNumber(field, raw)
+		if err != nil {
+			errs = append(errs, err)
+		} else if field == "volume_24h" && value < 0 {
+			errs = append(errs, fmt.Errorf("volume_24h must not be negative, got %v", value))
+		}
+	}
+	if raw, ok := fields["last_updated"]; ok {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			errs = append(errs, fmt.Errorf("last_updated must be a string, got %s", typeOf(raw)))
+		} else if _, err := time.Parse(time.RFC3339, s); err != nil {
+			errs = append(errs, fmt.Errorf("last_updated must be an RFC 3339 time, got %q", s))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var price models.LivePrice
+	if err := json.Unmarshal(body, &price); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode the response: %w", err)}
+	}
+	return &price, nil
+}
+
+// parseNumber decodes the field as a finite number.
+func parseNumber(field string, raw json.RawMessage) (float64, error) {
+	if typeOf(raw) != "number" {
+		return 0, fmt.Errorf("%s must be a number, got %s", field, typeOf(raw))
+	}
+	var value float64
+	if err := json.Unmarshal(raw, &value); err != nil || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%s is not a valid number: %s", field, raw)
+	}
+	return value, nil
+}
+
+// typeOf returns the JSON type of the raw value.
+func typeOf(raw json.RawMessage) string {
+	s := strings.TrimSpace(string(raw))
+	switch {
+	case s == "null":
+		return "null"
+	case s == "true" || s == "false":
+		return "boolean"
+	case strings.HasPrefix(s, `"`):
+		return "string"
+	case strings.HasPrefix(s, "{"):
+		return "object"
+	case strings.HasPrefix(s, "["):
+		return "array"
+	default:
+		return "number"
+	}
+}
@@ -0,0 +1,191 @@
+package pricefeed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testNow is the time the checkers of the tests measure the age of the prices against.
+var testNow = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestServer serves the body with the status and the Last-Modified time, if not zero.
+func newTestServer(t *testing.T, status int, lastModified time.Time, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !lastModified.IsZero() {
+			w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestChecker(opts ...Option) *Checker {
+	return New(append([]Option{WithClock(func() time.Time { return testNow })}, opts...)...)
+}
+
+// wantErrors fails the test unless every error contains its substring, in order.
+func wantErrors(t *testing.T, errs []error, want ...string) {
+	t.Helper()
+	if len(errs) != len(want) {
+		t.Fatalf("errors = %v, want %d errors containing %q", errs, len(want), want)
+	}
+	for i, err := range errs {
+		if !strings.Contains(err.Error(), want[i]) {
+			t.Errorf("errors[%d] = %q, want it to contain %q", i, err, want[i])
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	server := newTestServer(t, http.StatusOK, time.Time{},
+		`{"price_usd": 1.0001, "volume_24h": 12.5, "percent_change_24h": -0.02, "last_updated": "2026-01-01T11:59:00Z", "source": "ignored"}`)
+	price, errs := newTestChecker().Check(context.Background(), server.URL)
+	wantErrors(t, errs)
+	if price == nil || price.PriceUsd != 1.0001 {
+		t.Fatalf("Check() price = %+v, want price_usd 1.0001", price)
+	}
+	if price.Volume24h == nil || *price.Volume24h != 12.5 || price.PercentChange24h == nil || *price.PercentChange24h != -0.02 {
+		t.Errorf("Check() price = %+v, want volume_24h 12.5 and percent_change_24h -0.02", price)
+	}
+	if price.VolumeChange24h != nil || price.PercentChange1h != nil {
+		t.Errorf("Check() price = %+v, want the omitted fields nil", price)
+	}
+	if !price.LastUpdated.Equal(testNow.Add(-time.Minute)) {
+		t.Errorf("Check() last_updated = %v, want %v", price.LastUpdated, testNow.Add(-time.Minute))
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	server := newTestServer(t, http.StatusNotFound, time.Time{}, `{"price_usd": 1}`)
+	price, errs := newTestChecker().Check(context.Background(), server.URL)
+	if price != nil {
+		t.Errorf("Check() price = %+v, want nil", price)
+	}
+	wantErrors(t, errs, "unexpected status 404")
+}
+
+func TestCheckMaxSize(t *testing.T) {
+	body := `{"price_usd": 1, "last_updated": "2026-01-01T12:00:00Z", "padding": "` + strings.Repeat("x", 100) + `"}`
+	server := newTestServer(t, http.StatusOK, time.Time{}, body)
+
+	_, errs := newTestChecker(WithMaxSize(64)).Check(context.Background(), server.URL)
+	wantErrors(t, errs, "larger than 64 bytes")
+
+	_, errs = newTestChecker(WithMaxSize(int64(len(body)))).Check(context.Background(), server.URL)
+	wantErrors(t, errs)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	start := time.Now()
+	price, errs := newTestChecker(WithTimeout(50*time.Millisecond)).Check(context.Background(), server.URL)
+	if price != nil {
+		t.Errorf("Check() price = %+v, want nil", price)
+	}
+	wantErrors(t, errs, "request failed")
+	if !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("Check() error = %v, want it to wrap %v", errs[0], context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Check() took %s, want it to stop at the timeout", elapsed)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"minimal", `{"price_usd": 0.5}`, nil},
+		{"not an object", `[{"price_usd": 1}]`, []string{"must be a JSON object"}},
+		{"null", `null`, []string{"must be a JSON object"}},
+		{"missing price", `{"volume_24h": 1}`, []string{"price_usd is required"}},
+		{"string price", `{"price_usd": "1.00"}`, []string{"price_usd must be a number, got string"}},
+		{"null price", `{"price_usd": null}`, []string{"price_usd must be a number, got null"}},
+		{"zero price", `{"price_usd": 0}`, []string{"price_usd must be positive"}},
+		{"negative price", `{"price_usd": -1}`, []string{"price_usd must be positive"}},
+		{"huge price", `{"price_usd": 1e999}`, []string{"price_usd is not a valid number"}},
+		{"boolean change", `{"price_usd": 1, "percent_change_1h": true}`, []string{"percent_change_1h must be a number, got boolean"}},
+		{"object volume", `{"price_usd": 1, "volume_24h": {}}`, []string{"volume_24h must be a number, got object"}},
+		{"negative volume", `{"price_usd": 1, "volume_24h": -5}`, []string{"volume_24h must not be negative"}},
+		{"negative change", `{"price_usd": 1, "volume_change_24h": -5, "percent_change_90d": -99.9}`, nil},
+		{"numeric last_updated", `{"price_usd": 1, "last_updated": 1767268800}`, []string{"last_updated must be a string, got number"}},
+		{"invalid last_updated", `{"price_usd": 1, "last_updated": "2026-01-01 12:00"}`, []string{"last_updated must be an RFC 3339 time"}},
+		{"several problems", `{"price_usd": "1", "percent_change_7d": "2"}`, []string{"price_usd must be a number", "percent_change_7d must be a number"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, errs := Parse([]byte(tt.body))
+			wantErrors(t, errs, tt.want...)
+			if (price == nil) != (len(tt.want) > 0) {
+				t.Errorf("Parse() price = %+v, want a price only without errors", price)
+			}
+		})
+	}
+}
+
+func TestCheckAge(t *testing.T) {
+	tests := []struct {
+		name         string
+		lastUpdated  string
+		lastModified time.Time
+		want         []string
+		wantWrapped  error
+		wantUpdated  time.Time
+	}{
+		{"fresh last_updated", "2026-01-01T11:30:00Z", time.Time{}, nil, nil, testNow.Add(-30 * time.Minute)},
+		{"fresh Last-Modified", "", testNow.Add(-10 * time.Minute), nil, nil, testNow.Add(-10 * time.Minute)},
+		{"last_updated over Last-Modified", "2026-01-01T11:50:00Z", testNow.Add(-3 * time.Hour), nil, nil, testNow.Add(-10 * time.Minute)},
+		{"stale last_updated", "2026-01-01T10:00:00Z", time.Time{}, []string{"last updated 2h0m0s ago"}, ErrStale, testNow.Add(-2 * time.Hour)},
+		{"stale Last-Modified", "", testNow.Add(-90 * time.Minute), []string{"last updated 1h30m0s ago"}, ErrStale, testNow.Add(-90 * time.Minute)},
+		{"unknown age", "", time.Time{}, []string{"no last_updated and no Last-Modified"}, ErrUnknownAge, time.Time{}},
+		{"clock skew", "2026-01-01T12:04:00Z", time.Time{}, nil, nil, testNow.Add(4 * time.Minute)},
+		{"future", "2026-01-01T13:00:00Z", time.Time{}, []string{"is in the future"}, nil, testNow.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"price_usd": 1}`
+			if tt.lastUpdated != "" {
+				body = `{"price_usd": 1, "last_updated": "` + tt.lastUpdated + `"}`
+			}
+			server := newTestServer(t, http.StatusOK, tt.lastModified, body)
+			price, errs := newTestChecker(WithMaxAge(time.Hour)).Check(context.Background(), server.URL)
+			wantErrors(t, errs, tt.want...)
+			if price == nil {
+				t.Fatalf("Check() price = nil, want the price with its age problems")
+			}
+			for _, err := range errs {
+				for _, sentinel := range []error{ErrStale, ErrUnknownAge} {
+					if got, want := errors.Is(err, sentinel), sentinel == tt.wantWrapped; got != want {
+						t.Errorf("errors.Is(%q, %v) = %v, want %v", err, sentinel, got, want)
+					}
+				}
+			}
+			switch {
+			case tt.wantUpdated.IsZero() && price.LastUpdated != nil:
+				t.Errorf("Check() last_updated = %v, want nil", price.LastUpdated)
+			case !tt.wantUpdated.IsZero() && (price.LastUpdated == nil || !price.LastUpdated.Equal(tt.wantUpdated)):
+				t.Errorf("Check() last_updated = %v, want %v", price.LastUpdated, tt.wantUpdated)
+			}
+		})
+	}
+}
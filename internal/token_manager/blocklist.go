@@ -0,0 +1,85 @@
+package tokenmanager
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// blocklistPath is the path of the blocklist relative to the dist directory.
+const blocklistPath = "blocklist.json"
+
+// blockReason returns why the token is not listed, empty if it is listed.
+func blockReason(token models.Token) models.BlockReason {
+	switch {
+	case token.IsScam:
+		return models.BlockReason_SCAM
+	case token.IsDisabled:
+		return models.BlockReason_DISABLED
+	default:
+		return ""
+	}
+}
+
+// listedToken returns the token with only the addresses on the active networks that are not delisted,
+// it returns false if the token is disabled or a scam, or none of its addresses is listed.
+// the tokens without addresses are listed unless they are disabled, a scam or delisted.
+func (tm *tokenManager) listedToken(token models.Token) (models.Token, bool) {
+	if blockReason(token) != "" {
+		return models.Token{}, false
+	}
+	if len(token.Addresses) == 0 {
+		return token, token.Lifecycle.EffectiveStatus() != models.LifecycleStatus_DELISTED
+	}
+	addresses := make([]models.TokenAddress, 0, len(token.Addresses))
+	for _, address := range token.Addresses {
+		if addressLifecycle(&token, address).EffectiveStatus() == models.LifecycleStatus_DELISTED {
+			continue
+		}
+		network, ok := tm.networks[int64(address.NetworkId)]
+		if !ok || network.IsActive {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return models.Token{}, false
+	}
+	token.Addresses = addresses
+	return token, true
+}
+
+// writeBlocklist writes blocklist.json with the addresses of the disabled and the scam tokens of the profile,
+// including the addresses on the inactive networks, the wallets warn the users still holding them.
+func (tm *tokenManager) writeBlocklist(ctx context.Context, w *distWriter) error {
+	blocklist := models.Blocklist{Networks: make(map[int64][]models.BlockedAddress)}
+	for _, tokenUid := range sortedTokenUids(tm.tokens) {
+		reason := blockReason(*tm.tokens[tokenUid])
+		if reason == "" {
+			continue
+		}
+		token, ok := tm.profileToken(w.profile, *tm.tokens[tokenUid])
+		if !ok {
+			continue
+		}
+		for _, address := range token.Addresses {
+			network := tm.networks[int64(address.NetworkId)]
+			// the CAIP-19 id is informational, the addresses without one are blocked as well.
+			caip19, _ := network.CAIP19(address)
+			blocklist.Networks[network.Id] = append(blocklist.Networks[network.Id], models.BlockedAddress{
+				Address:   network.CanonicalAddress(address.Address),
+				Caip19:    caip19,
+				TokenUuid: tokenUid,
+				Symbol:    token.Symbol,
+				Name:      token.Name,
+				Reason:    reason,
+			})
+		}
+	}
+	for _, addresses := range blocklist.Networks {
+		sort.Slice(addresses, func(i, j int) bool {
+			return addresses[i].Address < addresses[j].Address
+		})
+	}
+	return w.writeJSON(blocklistPath, blocklist)
+}
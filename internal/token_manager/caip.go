@@ -0,0 +1,59 @@
+package tokenmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// caip19Dir is the directory of the CAIP-19 lookup files relative to the dist directory.
+const caip19Dir = "caip19"
+
+// setCaip19Ids sets the CAIP-19 asset id of every address of the published tokens.
+// it returns an error if two addresses share an asset id (e.g., two native tokens of a network),
+// their lookup files would overwrite each other.
+func (tm *tokenManager) setCaip19Ids(ctx context.Context, tokens map[string]*models.Token) error {
+	owners := make(map[string]string)
+	for _, tokenUid := range sortedTokenUids(tokens) {
+		token := tokens[tokenUid]
+		for i, address := range token.Addresses {
+			network, ok := tm.networks[int64(address.NetworkId)]
+			if !ok {
+				return fmt.Errorf("token %s: network %d not found", tokenUid, address.NetworkId)
+			}
+			caip19, err := network.CAIP19(address)
+			if err != nil {
+				return fmt.Errorf("token %s: %w", tokenUid, err)
+			}
+			if owner, ok := owners[caip19]; ok && owner != tokenUid {
+				return fmt.Errorf("token %s: CAIP-19 asset id %s is already used by the token %s", tokenUid, caip19, owner)
+			}
+			owners[caip19] = tokenUid
+			token.Addresses[i].Caip19 = caip19
+		}
+	}
+	return nil
+}
+
+// writeCaip19 writes the token of every address into caip19/:namespace/:reference/:asset_namespace/:asset_reference.json,
+// so the tokens can be looked up by their CAIP-19 asset id.
+func (tm *tokenManager) writeCaip19(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	for _, tokenUid := range sortedTokenUids(tokens) {
+		token := tokens[tokenUid]
+		for _, address := range token.Addresses {
+			err := w.writeJSON(caip19Path(address.Caip19), token)
+			if err != nil {
+				return fmt.Errorf("token %s: %w", tokenUid, err)
+			}
+		}
+	}
+	return nil
+}
+
+// caip19Path returns the path of the CAIP-19 lookup file of the asset id,
+// the ":" and "/" separators of the asset id are turned into directories.
+func caip19Path(caip19 string) string {
+	return caip19Dir + "/" + strings.NewReplacer(":", "/").Replace(caip19) + ".json"
+}
@@ -0,0 +1,39 @@
+package tokenmanager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+func TestSetCaip19Ids(t *testing.T) {
+	tm := &tokenManager{networks: map[int64]models.Network{
+		2: {Id: 2, NetworkType: models.NetworkType_NETWORK_TYPE_ETH_LIKE, ChainId: 1, CoinType: models.Coin_TYPE_ETH},
+	}}
+	eth := models.TokenAddress{NetworkId: 2, IsNative: true, TokenType: "COIN"}
+	tusd := models.TokenAddress{NetworkId: 2, Address: "0x0000000000085d4780B73119b644AE5ecd22b376", TokenType: "ERC20"}
+
+	tokens := map[string]*models.Token{
+		"eth":  {Uuid: "eth", OrderIndex: 1, Addresses: []models.TokenAddress{eth}},
+		"tusd": {Uuid: "tusd", OrderIndex: 2, Addresses: []models.TokenAddress{tusd}},
+	}
+	if err := tm.setCaip19Ids(context.Background(), tokens); err != nil {
+		t.Fatalf("setCaip19Ids() error = %v", err)
+	}
+	if got, want := tokens["eth"].Addresses[0].Caip19, "eip155:1/slip44:60"; got != want {
+		t.Errorf("native CAIP-19 = %q, want %q", got, want)
+	}
+	if got, want := tokens["tusd"].Addresses[0].Caip19, "eip155:1/erc20:0x0000000000085d4780b73119b644ae5ecd22b376"; got != want {
+		t.Errorf("erc20 CAIP-19 = %q, want %q", got, want)
+	}
+
+	// a second native token of the network would overwrite the lookup file of the first one.
+	tusd.IsNative, tusd.TokenType = true, "COIN"
+	tokens["tusd"].Addresses = []models.TokenAddress{tusd}
+	err := tm.setCaip19Ids(context.Background(), tokens)
+	if err == nil || !strings.Contains(err.Error(), "already used by the token eth") {
+		t.Errorf("setCaip19Ids() of two native tokens error = %v, want a duplicate asset id", err)
+	}
+}
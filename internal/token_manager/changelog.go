@@ -0,0 +1,72 @@
+package tokenmanager
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ma3xco/token-listing/internal/changelog"
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// changelogPath is the path of the changelog relative to the dist directory.
+const changelogPath = "changelog.json"
+
+// writeChangelog writes the changes of the published tokens since the previous registry state, if set.
+// the tokens leave the listing when they are disabled or flagged as a scam and rejoin it when they are
+// enabled again, so they are reported as disabled and enabled rather than as removed and added.
+func (tm *tokenManager) writeChangelog(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	if tm.previousTokens == nil {
+		return nil
+	}
+	list := []models.Token{}
+	for _, tokenUid := range sortedTokenUids(tokens) {
+		list = append(list, *tokens[tokenUid])
+	}
+	// the previous tokens are compared with the same profile and listing applied.
+	previous := []models.Token{}
+	previousBlocked := make(map[string]struct{})
+	for _, token := range tm.previousTokens {
+		if blockReason(token) != "" {
+			previousBlocked[token.Uuid] = struct{}{}
+		}
+		token, ok := tm.profileToken(w.profile, token)
+		if !ok {
+			continue
+		}
+		if token, ok := tm.listedToken(token); ok {
+			previous = append(previous, token)
+		}
+	}
+	c, err := changelog.Diff(previous, list)
+	if err != nil {
+		return err
+	}
+
+	removed := c.Removed[:0]
+	for _, ref := range c.Removed {
+		if token, ok := tm.tokens[ref.Uid]; ok && blockReason(*token) != "" {
+			c.Disabled = append(c.Disabled, ref)
+			continue
+		}
+		removed = append(removed, ref)
+	}
+	c.Removed = removed
+	added := c.Added[:0]
+	for _, ref := range c.Added {
+		if _, ok := previousBlocked[ref.Uid]; ok {
+			c.Enabled = append(c.Enabled, ref)
+			continue
+		}
+		added = append(added, ref)
+	}
+	c.Added = added
+	for _, refs := range [][]changelog.TokenRef{c.Disabled, c.Enabled} {
+		sort.Slice(refs, func(i, j int) bool {
+			return refs[i].Uid < refs[j].Uid
+		})
+	}
+
+	c.From = tm.previousLabel
+	c.To = tm.resolveSourceCommit(ctx)
+	return w.writeJSON(changelogPath, c)
+}
@@ -0,0 +1,182 @@
+package tokenmanager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// distWriter writes the build assets into a staging directory.
+// it keeps track of every file written, so the build can be verified
+// before the staging directory is swapped into the place of the dist directory.
+type distWriter struct {
+	// the staging directory, all the paths are relative to it.
+	root string
+
+	// the profile the tree is built for, refer to Profile.dir for its place in the dist directory.
+	profile Profile
+
+	// the key is the relative path (slash separated), the value is the written file.
+	files map[string]distFile
+}
+
+// distFile is a file written by the dist writer.
+type distFile struct {
+	size int64
+
+	// hex encoded SHA-256 of the file content.
+	sha256 string
+}
+
+func newDistWriter(root string, profile Profile) *distWriter {
+	return &distWriter{
+		root:    root,
+		profile: profile,
+		files:   make(map[string]distFile),
+	}
+}
+
+// writeFile writes the data into the relative path, creating the parent directories if needed.
+func (w *distWriter) writeFile(rel string, data []byte) error {
+	rel = filepath.ToSlash(filepath.Clean(rel))
+	if rel == "." || strings.HasPrefix(rel, "../") || filepath.IsAbs(rel) {
+		return fmt.Errorf("invalid dist path: %s", rel)
+	}
+	if _, ok := w.files[rel]; ok {
+		return fmt.Errorf("dist file %s is written twice", rel)
+	}
+	path := filepath.Join(w.root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	w.files[rel] = distFile{
+		size:   int64(len(data)),
+		sha256: hex.EncodeToString(sum[:]),
+	}
+	return nil
+}
+
+// writeJSON marshals the value and writes it into the relative path.
+func (w *distWriter) writeJSON(rel string, v any) error {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.writeFile(rel, bytes)
+}
+
+// count returns the number of written files that match the prefix and suffix.
+func (w *distWriter) count(prefix, suffix string) int {
+	n := 0
+	for rel := range w.files {
+		if strings.HasPrefix(rel, prefix) && strings.HasSuffix(rel, suffix) {
+			n++
+		}
+	}
+	return n
+}
+
+// paths returns the sorted list of written files.
+func (w *distWriter) paths() []string {
+	paths := make([]string, 0, len(w.files))
+	for rel := range w.files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// verify reads the staging directory back and checks that:
+// - every written file exists and has the recorded size and hash.
+// - there is no file in the staging directory that was not written by the writer.
+// - every json file can be parsed back.
+func (w *distWriter) verify() error {
+	found := 0
+	err := filepath.WalkDir(w.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(w.root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		file, ok := w.files[rel]
+		if !ok {
+			return fmt.Errorf("unexpected file %s in the build", rel)
+		}
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if int64(len(bytes)) != file.size {
+			return fmt.Errorf("file %s has size %d, expected %d", rel, len(bytes), file.size)
+		}
+		sum := sha256.Sum256(bytes)
+		if hex.EncodeToString(sum[:]) != file.sha256 {
+			return fmt.Errorf("file %s content does not match the written content", rel)
+		}
+		if strings.HasSuffix(rel, ".json") && !json.Valid(bytes) {
+			return fmt.Errorf("file %s is not a valid json", rel)
+		}
+		found++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if found != len(w.files) {
+		return fmt.Errorf("build contains %d files, expected %d", found, len(w.files))
+	}
+	return nil
+}
+
+// swapDir replaces the dst directory with the src directory.
+// an existing dst directory is exchanged with the src directory in a single atomic rename
+// where the platform supports it (refer to exchangeDirs), so dst is never missing, and the
+// previous dst directory is removed from src afterwards.
+// elsewhere the previous dst directory is kept aside until the src directory is in place.
+// either way any error leaves the previous dst directory untouched.
+func swapDir(src, dst string) error {
+	// os.MkdirTemp creates the directory with 0700, the dist directory is published as is.
+	if err := os.Chmod(src, 0755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(dst); os.IsNotExist(err) {
+		return os.Rename(src, dst)
+	} else if err != nil {
+		return err
+	}
+	exchanged, err := exchangeDirs(src, dst)
+	if err != nil {
+		return err
+	}
+	if exchanged {
+		return os.RemoveAll(src)
+	}
+	backup := fmt.Sprintf("%s.previous-%d", dst, time.Now().UnixNano())
+	if err := os.Rename(dst, backup); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		if rerr := os.Rename(backup, dst); rerr != nil {
+			return fmt.Errorf("%v (restoring the previous build failed: %v)", err, rerr)
+		}
+		return err
+	}
+	return os.RemoveAll(backup)
+}
@@ -0,0 +1,130 @@
+package tokenmanager
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// writeTree writes the files (by the slash separated relative path) into the directory.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readTree returns the files of the directory by the slash separated relative path.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files[filepath.ToSlash(rel)] = string(bytes)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// entries returns the names in the directory.
+func entries(t *testing.T, dir string) []string {
+	t.Helper()
+	list, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range list {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestSwapDir(t *testing.T) {
+	parent := t.TempDir()
+	src, dst := filepath.Join(parent, "staging"), filepath.Join(parent, "dist")
+	next := map[string]string{"tokens.json": "[2]", "mainnet/tokens.json": "[2]"}
+
+	// the first build has no previous dist directory.
+	writeTree(t, src, map[string]string{"tokens.json": "[1]", "old.json": "{}"})
+	if err := swapDir(src, dst); err != nil {
+		t.Fatalf("swapDir() without a dist directory error = %v", err)
+	}
+	writeTree(t, src, next)
+	if err := swapDir(src, dst); err != nil {
+		t.Fatalf("swapDir() error = %v", err)
+	}
+	if got := readTree(t, dst); !maps.Equal(got, next) {
+		t.Errorf("dist after swapDir() = %v, want %v", got, next)
+	}
+	if got := entries(t, parent); len(got) != 1 || got[0] != "dist" {
+		t.Errorf("swapDir() left %v behind, want only dist", got)
+	}
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("dist mode = %v, want 0755", info.Mode().Perm())
+	}
+}
+
+func TestBuildTokensFailureKeepsDist(t *testing.T) {
+	parent := t.TempDir()
+	distDir := filepath.Join(parent, "dist")
+	previous := map[string]string{
+		"tokens.json":         `[{"uuid": "previous"}]`,
+		"manifest.json":       `{"registry_version": 7}`,
+		"logos/previous.png":  "\x89PNG",
+		"mainnet/tokens.json": "[]",
+	}
+	writeTree(t, distDir, previous)
+
+	tm := &tokenManager{}
+	tm.setDefaults()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	tm.logger = logger
+	tm.distDir = distDir
+	tm.tags[models.StablecoinTag] = models.Tag{Id: models.StablecoinTag, Name: "Stablecoin", Description: "Pegged to a fiat currency.", Category: models.TagCategory_ASSET}
+	tm.tagDefinitions = []models.Tag{tm.tags[models.StablecoinTag]}
+	// the build fails in the middle, the network of the address does not exist.
+	tm.tokens["broken"] = &models.Token{Uuid: "broken", Symbol: "BRK", Addresses: []models.TokenAddress{
+		{NetworkId: 99, Address: "0x00000000000000000000000000000000000000aa", TokenType: "ERC20"},
+	}}
+
+	err := tm.BuildTokens(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "network 99 not found") {
+		t.Fatalf("BuildTokens() error = %v, want the build to fail on the unknown network", err)
+	}
+	if got := readTree(t, distDir); !maps.Equal(got, previous) {
+		t.Errorf("dist after a failed build = %v, want the previous build %v", got, previous)
+	}
+	if got := entries(t, parent); len(got) != 1 || got[0] != "dist" {
+		t.Errorf("the failed build left %v behind, want only dist", got)
+	}
+}
@@ -0,0 +1,67 @@
+package tokenmanager
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Severity is the severity of a validation finding.
+type Severity int
+
+const (
+	// SeverityError fails the validation.
+	SeverityError Severity = iota
+	// SeverityWarning is reported to the reviewers, but does not fail the validation.
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// Finding is a validation error with a severity.
+// the validation errors without a severity are errors.
+type Finding struct {
+	Severity Severity
+	Message  string
+}
+
+func (f *Finding) Error() string {
+	return f.Message
+}
+
+// warningf returns a finding with the warning severity.
+func warningf(format string, args ...any) error {
+	return &Finding{Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)}
+}
+
+// SeverityOf returns the severity of the validation error.
+func SeverityOf(err error) Severity {
+	var finding *Finding
+	if errors.As(err, &finding) {
+		return finding.Severity
+	}
+	return SeverityError
+}
+
+// IsWarning reports whether the validation error is a warning, which does not fail the validation.
+func IsWarning(err error) bool {
+	return SeverityOf(err) == SeverityWarning
+}
+
+// HasErrors reports whether any of the validation errors fails the validation.
+func HasErrors(errs []error) bool {
+	for _, err := range errs {
+		if !IsWarning(err) {
+			return true
+		}
+	}
+	return false
+}
@@ -10,8 +10,12 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/ma3xco/token-listing/internal/imaging"
 	"github.com/ma3xco/token-listing/internal/models"
 	"github.com/sirupsen/logrus"
 )
@@ -21,12 +25,24 @@ func (tm *tokenManager) setDefaults() {
 	logger.ReportCaller = true
 	tm.logger = logger
 
+	tm.distDir = "./dist"
+	tm.cdnBaseUrl = "https://ma3xco.github.io/token-listing"
+	tm.maxLogoPadding = 8
+	tm.maxLogoHashDistance = 10
+	tm.profiles = []Profile{ProfileAll}
+
 	tm.networks = make(map[int64]models.Network)
 	tm.networkAddressRegex = make(map[int64]*regexp.Regexp)
 	tm.tokens = make(map[string]*models.Token)
 	tm.featuredTokens = make(map[string]struct{})
+	tm.svgLogos = make(map[string]struct{})
+	tm.svgNetworkIcons = make(map[int64]struct{})
+	tm.logoHashes = make(map[string]imaging.Hash)
+	tm.localizations = make(map[string]map[string]models.Localization)
+	tm.tags = make(map[string]models.Tag)
 	tm.coinMarketcapIdToTokenUid = make(map[int64]string)
 	tm.networkTokenAddresses = make(map[int64]map[string]string)
+	tm.canonicalTokenAddresses = make(map[int64]map[string]string)
 }
 
 // validateURL validates that a URL is safe and uses allowed schemes
@@ -70,6 +86,10 @@ func (tm *tokenManager) init(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	err = tm.loadTags(ctx)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -113,21 +133,37 @@ func (tm *tokenManager) WalkThrough(ctx context.Context) (int, error) {
 		if err != nil {
 			return 0, err
 		}
-		_, err = os.ReadFile(fmt.Sprintf("tokens/%s/logo.png", tknUid))
+		logo, err := os.ReadFile(fmt.Sprintf("tokens/%s/logo.png", tknUid))
 		if err != nil {
 			return 0, err
 		}
 		// Token exists and logo exists, load the token into the memory.
 		tm.tokens[tknUid] = &token
+		// The invalid logos are reported by the validation.
+		if hash, err := hashLogo(logo); err == nil {
+			tm.logoHashes[tknUid] = hash
+		}
+		// The svg logo is optional.
+		_, err = os.Stat(fmt.Sprintf("tokens/%s/logo.svg", tknUid))
+		if err == nil {
+			tm.svgLogos[tknUid] = struct{}{}
+		} else if !os.IsNotExist(err) {
+			return 0, err
+		}
+		// The translations are optional.
+		if err := tm.loadLocalizations(tknUid); err != nil {
+			return 0, err
+		}
 		if token.IsFeatured {
 			tm.featuredTokens[tknUid] = struct{}{}
 		}
 		for _, address := range token.Addresses {
-			reg, ok := tm.networkAddressRegex[int64(address.NetworkId)]
-			if !ok {
-				return 0, errors.New("network address regex not found")
+			if _, ok := tm.networks[int64(address.NetworkId)]; !ok {
+				return 0, fmt.Errorf("network %d not found", address.NetworkId)
 			}
-			if !reg.MatchString(address.Address) && address.NetworkId != 1 {
+			// The networks with an invalid address regex are reported by the network validation.
+			reg, ok := tm.networkAddressRegex[int64(address.NetworkId)]
+			if ok && !reg.MatchString(address.Address) && address.NetworkId != 1 {
 				return 0, fmt.Errorf("network address regex not match for network %d and address %s", address.NetworkId, address.Address)
 			}
 			if _, ok := tm.networkTokenAddresses[int64(address.NetworkId)][address.Address]; ok {
@@ -137,6 +173,14 @@ func (tm *tokenManager) WalkThrough(ctx context.Context) (int, error) {
 				tm.networkTokenAddresses[int64(address.NetworkId)] = make(map[string]string)
 			}
 			tm.networkTokenAddresses[int64(address.NetworkId)][address.Address] = tknUid
+			canonical := tm.networks[int64(address.NetworkId)].CanonicalAddress(address.Address)
+			if _, ok := tm.canonicalTokenAddresses[int64(address.NetworkId)][canonical]; ok {
+				return 0, fmt.Errorf("token address already exists for network %d and address %s", address.NetworkId, address.Address)
+			}
+			if tm.canonicalTokenAddresses[int64(address.NetworkId)] == nil {
+				tm.canonicalTokenAddresses[int64(address.NetworkId)] = make(map[string]string)
+			}
+			tm.canonicalTokenAddresses[int64(address.NetworkId)][canonical] = tknUid
 			tm.coinMarketcapIdToTokenUid[int64(address.NetworkId)] = tknUid
 		}
 
@@ -155,8 +199,30 @@ func (tm *tokenManager) CreateTokenTemplate(ctx context.Context, uid string) err
 // - The token must have a symbol.
 // - The token must have a logo.
 // - The token must have a description.
+// - name and description have no surrounding whitespace and are within the length limits,
+// as are their translations, refer to validateLocalizations.
 // - The token must have a coin marketcap id or price url.
-// - logo png is not too large and have 64x64 size.
+// - logo png is not too large, square and between 64x64 and 1024x1024.
+// - logo svg, if provided, is a safe and self-contained svg document.
+// - symbol and name do not look like the symbol or name of a featured, stable or blue-checkmark token,
+// unless the tokens list each other in same_asset_as.
+// - tags are defined in tags/tags.json, and a token has the stablecoin tag if and only if it is a stable token.
+// - lifecycle of the token and its addresses is consistent and the successors are addresses of the registry,
+// refer to validateLifecycle.
+// - origin of every address is consistent and its source is an address of the registry, refer to validateOrigins.
+// - live price url responds with the price contract, when a price checker is set, refer to WithPriceChecker.
+// - ERC-20 addresses match the decimals, symbol and upgradeable flag of their contracts,
+// when an on-chain verifier is set, refer to WithOnchainVerifier.
+// Warnings (refer to IsWarning):
+// - logo png without alpha, with a wide transparent border, nearly blank,
+// near-uniform color or with a palette.
+// - logo png that looks like the logo of a token with a different symbol or of a featured token.
+// - symbol or name that looks like the symbol or name of another token.
+// - token flagged as a scam, it is published in the blocklist only.
+// - origin source that belongs to an unrelated token.
+// - live price that is stale or has no update time, when a price checker is set.
+// - address name that differs from its contract, or an upgradeable address that is not an EIP-1967 proxy,
+// when an on-chain verifier is set.
 func (tm *tokenManager) ValidateTokens(ctx context.Context) map[string][]error {
 	validationErrors := make(map[string][]error)
 
@@ -178,15 +244,35 @@ func (tm *tokenManager) ValidateTokens(ctx context.Context) map[string][]error {
 			errors = append(errors, fmt.Errorf("token description is required"))
 		}
 
-		// Validate logo PNG URL (required)
-		if err := tm.validateURL(token.LogoPngUrl, "logo PNG", true); err != nil {
+		// Validate the length of the name and the description, and their translations
+		errors = append(errors, validateText("token name", token.Name, maxNameLength)...)
+		errors = append(errors, validateText("token description", token.Description, maxDescriptionLength)...)
+		errors = append(errors, tm.validateLocalizations(tokenUid)...)
+
+		// Validate logo PNG URL format if provided (optional, the build points it at the CDN copy)
+		if err := tm.validateURL(token.LogoPngUrl, "logo PNG", false); err != nil {
 			errors = append(errors, err)
 		}
 
-		// Validate logo file exists and is 64x64 PNG
+		// Validate logo file exists and is a square PNG
 		logoPath := fmt.Sprintf("tokens/%s/logo.png", tokenUid)
 		if err := tm.validateLogoFile(logoPath); err != nil {
 			errors = append(errors, fmt.Errorf("logo file validation failed: %v", err))
+		} else {
+			// Report the logo quality issues as warnings
+			if err := tm.validateLogoSize(logoPath); err != nil {
+				errors = append(errors, err)
+			}
+			errors = append(errors, tm.analyzeLogo(logoPath)...)
+			// Report the logos that look like the logos of other tokens
+			errors = append(errors, tm.findSimilarLogos(tokenUid)...)
+		}
+
+		// Validate logo.svg if provided (optional)
+		if _, ok := tm.svgLogos[tokenUid]; ok {
+			if err := tm.validateSvgFile(fmt.Sprintf("tokens/%s/logo.svg", tokenUid)); err != nil {
+				errors = append(errors, fmt.Errorf("svg logo validation failed: %v", err))
+			}
 		}
 
 		// Validate either CoinMarketCap ID or LivePriceUrl is provided
@@ -199,9 +285,11 @@ func (tm *tokenManager) ValidateTokens(ctx context.Context) map[string][]error {
 			errors = append(errors, fmt.Errorf("CoinMarketCap ID must be positive"))
 		}
 
-		// Validate LivePriceUrl format if provided (optional)
+		// Validate LivePriceUrl format if provided (optional), and its response when the price checker is set
 		if err := tm.validateURL(token.LivePriceUrl, "LivePrice", false); err != nil {
 			errors = append(errors, err)
+		} else if tm.priceChecker != nil && token.LivePriceUrl != "" {
+			errors = append(errors, tm.checkLivePrice(ctx, token.LivePriceUrl)...)
 		}
 
 		// Validate URLs format (all optional)
@@ -243,9 +331,27 @@ func (tm *tokenManager) ValidateTokens(ctx context.Context) map[string][]error {
 			}
 		}
 
-		// Validate scam flag
+		// Validate the tags against the tag definitions
+		errors = append(errors, tm.validateTokenTags(tokenUid)...)
+
+		// Validate the tokens listed as the same asset, and the lookalike symbols and names
+		errors = append(errors, tm.validateSameAsset(tokenUid)...)
+		errors = append(errors, tm.findLookalikes(tokenUid)...)
+
+		// Validate the lifecycle of the token and its addresses
+		errors = append(errors, tm.validateLifecycle(tokenUid)...)
+
+		// Validate the origins of the token addresses
+		errors = append(errors, tm.validateOrigins(tokenUid)...)
+
+		// Verify the token addresses against their contracts when the on-chain verifier is set
+		if tm.onchainVerifier != nil {
+			errors = append(errors, tm.verifyOnchain(ctx, tokenUid)...)
+		}
+
+		// Validate scam flag, the scam tokens are only published in the blocklist
 		if token.IsScam {
-			errors = append(errors, fmt.Errorf("scam tokens are not allowed"))
+			errors = append(errors, warningf("token is flagged as a scam, it is published in the blocklist only"))
 		}
 
 		if len(errors) > 0 {
@@ -355,7 +461,8 @@ func (tm *tokenManager) ValidateTokensForForkByUids(ctx context.Context, tokenUi
 	return validationErrors
 }
 
-// validateLogoFile validates that the logo file exists and is a 64x64 PNG
+// validateLogoFile validates that the logo file exists and is a square PNG between 64x64 and 1024x1024,
+// the token logos smaller than 256x256 get a warning, refer to validateLogoSize.
 func (tm *tokenManager) validateLogoFile(logoPath string) error {
 	// Check if file exists
 	fileInfo, err := os.Stat(logoPath)
@@ -392,8 +499,11 @@ func (tm *tokenManager) validateLogoFile(logoPath string) error {
 	width := config.Width
 	height := config.Height
 
-	if width != 64 || height != 64 {
-		return fmt.Errorf("logo must be exactly 64x64 pixels, got: %dx%d", width, height)
+	if width != height {
+		return fmt.Errorf("logo must be square, got: %dx%d", width, height)
+	}
+	if width < minLegacyLogoSize || width > maxLogoSize {
+		return fmt.Errorf("logo must be between %dx%d and %dx%d pixels, got: %dx%d", minLegacyLogoSize, minLegacyLogoSize, maxLogoSize, maxLogoSize, width, height)
 	}
 
 	return nil
@@ -477,47 +587,217 @@ func (tm *tokenManager) validateTokenAddress(address models.TokenAddress, index
 // the build assets contains
 // - tokens.json (all tokens list) - done
 // - :network_id/:tokenAddress.json (the token details with all token addresses Hashmap) - done
-// - :network_id/:tokenAddress/token_address.json (the token address details only) - done
+// - :network_id/:tokenAddress/token_address.json (the token address details only, with the explorer URL and CAIP-19 id) - done
+// - caip19/:namespace/:reference/:asset_namespace/:asset_reference.json (the token by its CAIP-19 asset id) - done
 // - tokens/:tokenUid.json (the token Hashmap) - done
+// - tokens/:tokenUid.png & tokens/:tokenUid/logo_:size.(png|webp) (the logo variants) - done
+// - tokens/:tokenUid.svg (the svg logo, when provided) - done
 // - :coin_marketcap_id.json (the coin marketcap Hashmap) - SKIPPED
 // - tokens.featured.json (the featured tokens list) - done
+// - tags.json & tags/:tag.json (the tag definitions and the tokens of every tag) - done
+// - :locale/tokens.json (the tokens list with the translated names and descriptions, falling back to english) - done
+// - blocklist.json (the addresses of the disabled and the scam tokens by the network, with the reason) - done
+// - migrations.json (the addresses with a successor by the network, refer to models.Lifecycle) - done
+// - asset_graph.json (the canonical, bridged and wrapped addresses and their sources, refer to models.Origin) - done
+// - networks.json & networks/:networkId.json (the networks list and the network details) - done
+// - networks/:networkId.png & networks/:networkId.svg (the network icons) - done
+// - search_index.json (the search index over the symbols, names and addresses) - done
+// - logo_hashes.json (the perceptual hashes of the logos) - done
+// - changelog.json (the changes since the previous registry state, when set) - done
+// - manifest.json (the artifacts with their hashes and the registry version) - done
+// - manifest.json.sig & signing_key.pem (the detached signature and the public key, when a signing key is set) - done
+// the listed assets exclude the disabled, the scam and the delisted tokens and the inactive networks, refer to listedToken.
+// every build profile publishes these assets into its own tree, the all profile at the root
+// of the dist directory and the others into dist/:profile, refer to WithProfiles.
+// the assets are written into a staging directory first, verified and then
+// swapped into the place of the dist directory, so a failed build leaves the
+// previous dist directory untouched.
+// the build fails if a network or a tag definition has validation errors, refer to ValidateNetworks and ValidateTags,
+// or if the artifact signatures are enabled without a signing key.
 // it returns an error if any.
 func (tm *tokenManager) BuildTokens(ctx context.Context) error {
-	// clean the dist directory
-	err := os.RemoveAll("./dist")
+	if tm.signArtifacts && tm.signingKey == nil {
+		return errors.New("artifact signatures require a signing key")
+	}
+	// the explorer URLs and the CAIP-19 ids are derived from the network definitions,
+	// so the registry is never built from invalid networks.
+	for networkId, errs := range tm.ValidateNetworks(ctx) {
+		for _, err := range errs {
+			if !IsWarning(err) {
+				return fmt.Errorf("network %d is invalid: %w", networkId, err)
+			}
+		}
+	}
+	// the tag definitions are published as is.
+	for tagId, errs := range tm.ValidateTags(ctx) {
+		for _, err := range errs {
+			if !IsWarning(err) {
+				return fmt.Errorf("tag %s is invalid: %w", tagId, err)
+			}
+		}
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(filepath.Clean(tm.distDir)), ".dist-staging-")
+	if err != nil {
+		return err
+	}
+	// after a successful swap the staging directory does not exist anymore.
+	defer os.RemoveAll(staging)
+
+	// the all profile is built first, its tree is the root of the staging directory
+	// and is verified before the trees of the other profiles are created inside it.
+	profiles := slices.Clone(tm.profiles)
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i] == ProfileAll && profiles[j] != ProfileAll
+	})
+	for _, profile := range profiles {
+		err = tm.buildProfile(ctx, newDistWriter(filepath.Join(staging, profile.dir()), profile))
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", profile, err)
+		}
+	}
+	return swapDir(staging, tm.distDir)
+}
+
+// buildProfile builds the tree of the profile of the writer.
+func (tm *tokenManager) buildProfile(ctx context.Context, w *distWriter) error {
+	tokens := tm.publishedTokens(w.profile)
+	err := tm.setExplorerUrls(ctx, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.setCaip19Ids(ctx, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeLogos(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeNetworks(ctx, w)
+	if err != nil {
+		return err
+	}
+	err = tm.writeAssets(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeCaip19(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeBlocklist(ctx, w)
+	if err != nil {
+		return err
+	}
+	err = tm.writeMigrations(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeAssetGraph(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeTags(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeLocalizations(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeSearchIndex(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeLogoHashes(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writeChangelog(ctx, w, tokens)
+	if err != nil {
+		return err
+	}
+	err = tm.writePublicKey(ctx, w)
+	if err != nil {
+		return err
+	}
+	_, err = tm.writeManifest(ctx, w)
 	if err != nil {
 		return err
 	}
-	err = os.Mkdir("./dist", 0755)
+	err = tm.writeSignatures(ctx, w)
 	if err != nil {
 		return err
 	}
+	err = tm.verifyAssets(ctx, w, tokens)
+	if err != nil {
+		return fmt.Errorf("build verification failed: %w", err)
+	}
+	return nil
+}
+
+// publishedTokens returns the copies of the listed tokens in the memory the profile publishes,
+// refer to listedToken, the build steps are free to rewrite the copies without touching the loaded tokens.
+func (tm *tokenManager) publishedTokens(profile Profile) map[string]*models.Token {
+	tokens := make(map[string]*models.Token, len(tm.tokens))
+	for tokenUid, token := range tm.tokens {
+		published, ok := tm.profileToken(profile, *token)
+		if !ok {
+			continue
+		}
+		published, ok = tm.listedToken(published)
+		if !ok {
+			continue
+		}
+		published.Addresses = append([]models.TokenAddress(nil), published.Addresses...)
+		tokens[tokenUid] = &published
+	}
+	return tokens
+}
+
+// setExplorerUrls sets the explorer URL of every non-native address of the published tokens.
+func (tm *tokenManager) setExplorerUrls(ctx context.Context, tokens map[string]*models.Token) error {
+	for tokenUid, token := range tokens {
+		for i, address := range token.Addresses {
+			if address.IsNative {
+				continue
+			}
+			network, ok := tm.networks[int64(address.NetworkId)]
+			if !ok {
+				return fmt.Errorf("token %s: network %d not found", tokenUid, address.NetworkId)
+			}
+			explorerUrl, err := network.ExplorerURL(models.ExplorerKind_TOKEN, address.Address)
+			if err != nil {
+				return fmt.Errorf("token %s: %w", tokenUid, err)
+			}
+			token.Addresses[i].ExplorerUrl = explorerUrl
+		}
+	}
+	return nil
+}
+
+// writeAssets writes the build assets of the published tokens using the dist writer.
+func (tm *tokenManager) writeAssets(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
 	// build tokens.json
 	{
-		var tokens []models.Token
-		for i := range tm.tokens {
-			tokens = append(tokens, *tm.tokens[i])
+		list := []models.Token{}
+		for _, tokenUid := range sortedTokenUids(tokens) {
+			list = append(list, *tokens[tokenUid])
 		}
-		bytes, err := json.Marshal(tokens)
-		if err != nil {
-			return err
-		}
-		err = os.WriteFile("./dist/tokens.json", bytes, 0644)
+		err := w.writeJSON("tokens.json", list)
 		if err != nil {
 			return err
 		}
 	}
 	// build tokens.featured.json
 	{
-		var tokens []models.Token
-		for i := range tm.featuredTokens {
-			tokens = append(tokens, *tm.tokens[i])
-		}
-		bytes, err := json.Marshal(tokens)
-		if err != nil {
-			return err
+		list := []models.Token{}
+		for _, tokenUid := range sortedTokenUids(tokens) {
+			if _, ok := tm.featuredTokens[tokenUid]; ok {
+				list = append(list, *tokens[tokenUid])
+			}
 		}
-		err = os.WriteFile("./dist/tokens.featured.json", bytes, 0644)
+		err := w.writeJSON("tokens.featured.json", list)
 		if err != nil {
 			return err
 		}
@@ -525,48 +805,19 @@ func (tm *tokenManager) BuildTokens(ctx context.Context) error {
 
 	// build :network_id/:tokenAddress.json
 	{
-		for networkId, tokenAddresses := range tm.networkTokenAddresses {
-			os.Mkdir(fmt.Sprintf("./dist/%d", networkId), 0755)
-			for tokenAddress, tokenUid := range tokenAddresses {
-				token, ok := tm.tokens[tokenUid]
-				if !ok {
-					return fmt.Errorf("token %s not found", tokenUid)
-				}
-				bytes, err := json.Marshal(token)
-				if err != nil {
-					return err
-				}
-				err = os.WriteFile(fmt.Sprintf("./dist/%d/%s.json", networkId, tokenAddress), bytes, 0644)
+		for _, token := range tokens {
+			for _, address := range token.Addresses {
+				err := w.writeJSON(fmt.Sprintf("%d/%s.json", address.NetworkId, address.Address), token)
 				if err != nil {
 					return err
 				}
-
 			}
 		}
-
 	}
-	// build tokens/:tokenUid.json & tokens/:tokenUid.png
+	// build tokens/:tokenUid.json
 	{
-		os.Mkdir("./dist/tokens", 0755)
-		for tokenUid := range tm.tokens {
-			token, ok := tm.tokens[tokenUid]
-			if !ok {
-				return fmt.Errorf("token %s not found", tokenUid)
-			}
-			bytes, err := json.Marshal(token)
-			if err != nil {
-				return err
-			}
-			err = os.WriteFile(fmt.Sprintf("./dist/tokens/%s.json", tokenUid), bytes, 0644)
-			if err != nil {
-				return err
-			}
-			// load the logo.png
-			logoPng, err := os.ReadFile(fmt.Sprintf("tokens/%s/logo.png", tokenUid))
-			if err != nil {
-				return err
-			}
-			err = os.WriteFile(fmt.Sprintf("./dist/tokens/%s.png", tokenUid), logoPng, 0644)
+		for tokenUid, token := range tokens {
+			err := w.writeJSON(fmt.Sprintf("tokens/%s.json", tokenUid), token)
 			if err != nil {
 				return err
 			}
@@ -574,21 +825,9 @@ func (tm *tokenManager) BuildTokens(ctx context.Context) error {
 	}
 	// :network_id/:tokenAddress/token_address.json (the token address details only)
 	{
-		for networkId := range tm.networks {
-			err := os.Mkdir(fmt.Sprintf("./dist/%d", networkId), 0755)
-			if err != nil && !os.IsExist(err) {
-				return err
-			}
-		}
-		for index := range tm.tokens {
-			token := tm.tokens[index]
+		for _, token := range tokens {
 			for _, address := range token.Addresses {
-				os.Mkdir(fmt.Sprintf("./dist/%d/%s", address.NetworkId, address.Address), 0755)
-				bytes, err := json.Marshal(address)
-				if err != nil {
-					return err
-				}
-				err = os.WriteFile(fmt.Sprintf("./dist/%d/%s/token_address.json", address.NetworkId, address.Address), bytes, 0644)
+				err := w.writeJSON(fmt.Sprintf("%d/%s/token_address.json", address.NetworkId, address.Address), address)
 				if err != nil {
 					return err
 				}
@@ -598,19 +837,110 @@ func (tm *tokenManager) BuildTokens(ctx context.Context) error {
 	return nil
 }
 
-func (tm *tokenManager) loadNetworks(ctx context.Context) error {
-	bytes, err := os.ReadFile("networks/networks.json")
+// verifyAssets verifies the written assets before they are published.
+// it checks the number of the generated files against the published tokens,
+// and reads every written file back from the staging directory.
+func (tm *tokenManager) verifyAssets(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	addresses := 0
+	svgLogos := 0
+	logoVariants := 0
+	for tokenUid, token := range tokens {
+		addresses += len(token.Addresses)
+		logoVariants += len(token.Logos.Png)
+		if _, ok := tm.svgLogos[tokenUid]; ok {
+			svgLogos++
+		}
+	}
+	expected := []struct {
+		name   string
+		count  int
+		expect int
+	}{
+		{"tokens/*.json", w.count("tokens/", ".json"), len(tokens)},
+		{"tokens/*.png", w.count("tokens/", ".png"), len(tokens) + logoVariants},
+		{"tokens/*.webp", w.count("tokens/", ".webp"), logoVariants},
+		{"tokens/*.svg", w.count("tokens/", ".svg"), svgLogos},
+		{":network_id/:tokenAddress/token_address.json", w.count("", "/token_address.json"), addresses},
+		{"caip19/**/*.json", w.count(caip19Dir+"/", ".json"), addresses},
+	}
+	for _, e := range expected {
+		if e.count != e.expect {
+			return fmt.Errorf("build contains %d %s files, expected %d", e.count, e.name, e.expect)
+		}
+	}
+	err := w.verify()
 	if err != nil {
 		return err
 	}
-	var networks []models.Network
-	err = json.Unmarshal(bytes, &networks)
+	err = tm.verifyManifest(ctx, w)
+	if err != nil {
+		return err
+	}
+	return tm.verifySignatures(ctx, w)
+}
+
+// sortedTokenUids returns the token uids sorted by the order index and then by the uid,
+// so the lists are built in a stable order and their hashes only change with the content.
+func sortedTokenUids(tokens map[string]*models.Token) []string {
+	uids := make([]string, 0, len(tokens))
+	for tokenUid := range tokens {
+		uids = append(uids, tokenUid)
+	}
+	sort.Slice(uids, func(i, j int) bool {
+		a, b := tokens[uids[i]], tokens[uids[j]]
+		if a.OrderIndex != b.OrderIndex {
+			return a.OrderIndex < b.OrderIndex
+		}
+		return uids[i] < uids[j]
+	})
+	return uids
+}
+
+// loadNetworks loads the networks from networks/:networkId/meta.json,
+// the folder name must be a network id and the folder must have an icon.png.
+// the invalid network definitions are loaded as is and reported by ValidateNetworks.
+func (tm *tokenManager) loadNetworks(ctx context.Context) error {
+	entries, err := os.ReadDir("networks")
 	if err != nil {
 		return err
 	}
-	for _, network := range networks {
-		tm.networks[int64(network.Id)] = network
-		tm.networkAddressRegex[int64(network.Id)] = regexp.MustCompile(network.AddressRegex)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		networkId, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil || networkId <= 0 || strconv.FormatInt(networkId, 10) != entry.Name() {
+			tm.logger.Warnf("Invalid network folder name: %s", entry.Name())
+			continue
+		}
+		metaFile, err := os.ReadFile(fmt.Sprintf("networks/%d/meta.json", networkId))
+		if err != nil {
+			return err
+		}
+		var network models.Network
+		err = json.Unmarshal(metaFile, &network)
+		if err != nil {
+			return fmt.Errorf("network %d: %w", networkId, err)
+		}
+		_, err = os.Stat(fmt.Sprintf("networks/%d/icon.png", networkId))
+		if err != nil {
+			return err
+		}
+		// The svg icon is optional.
+		_, err = os.Stat(fmt.Sprintf("networks/%d/icon.svg", networkId))
+		if err == nil {
+			tm.svgNetworkIcons[networkId] = struct{}{}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		// The invalid CAIP-2 chain ids and address regexes are reported by the validation.
+		if caip2, err := network.CAIP2(); err == nil {
+			network.Caip2 = caip2
+		}
+		tm.networks[networkId] = network
+		if regex, err := regexp.Compile(network.AddressRegex); err == nil {
+			tm.networkAddressRegex[networkId] = regex
+		}
 	}
 	return nil
 }
@@ -0,0 +1,176 @@
+package tokenmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// The length limits of the names and the descriptions, in characters.
+const (
+	maxNameLength        = 64
+	maxDescriptionLength = 1000
+)
+
+// loadLocalizations loads the translations of the token from tokens/:tokenUid/i18n/:locale.json, if any.
+// the locales are reported by the validation, refer to validateLocalizations.
+func (tm *tokenManager) loadLocalizations(tokenUid string) error {
+	dir := fmt.Sprintf("tokens/%s/i18n", tokenUid)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		locale, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			return fmt.Errorf("token %s: unexpected i18n/%s, the translations are i18n/<locale>.json files", tokenUid, entry.Name())
+		}
+		bytes, err := os.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		var localization models.Localization
+		if err := decodeStrict(bytes, &localization); err != nil {
+			return fmt.Errorf("token %s: i18n/%s: %w", tokenUid, entry.Name(), err)
+		}
+		if tm.localizations[tokenUid] == nil {
+			tm.localizations[tokenUid] = make(map[string]models.Localization)
+		}
+		tm.localizations[tokenUid][locale] = localization
+	}
+	return nil
+}
+
+// decodeStrict decodes the json into v, rejecting the unknown fields.
+func decodeStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// validateLocalizations validates the translations of the token:
+// - the locale is a BCP-47 language tag other than the default locale, refer to models.IsValidLocale.
+// - the translation has a name or a description, without surrounding whitespace.
+// - the name and the description are within the length limits.
+func (tm *tokenManager) validateLocalizations(tokenUid string) []error {
+	var errors []error
+	for _, locale := range sortedKeys(tm.localizations[tokenUid]) {
+		localization := tm.localizations[tokenUid][locale]
+		switch {
+		case !models.IsValidLocale(locale):
+			errors = append(errors, fmt.Errorf("i18n/%s.json: invalid locale, expected a BCP-47 tag such as \"de\", \"zh-Hant\" or \"pt-BR\"", locale))
+			continue
+		case locale == models.DefaultLocale:
+			errors = append(errors, fmt.Errorf("i18n/%s.json: the %s name and description belong in meta.json", locale, locale))
+			continue
+		case localization.Name == "" && localization.Description == "":
+			errors = append(errors, fmt.Errorf("i18n/%s.json: name or description is required", locale))
+			continue
+		}
+		errors = append(errors, validateText(fmt.Sprintf("i18n/%s.json: name", locale), localization.Name, maxNameLength)...)
+		errors = append(errors, validateText(fmt.Sprintf("i18n/%s.json: description", locale), localization.Description, maxDescriptionLength)...)
+	}
+	return errors
+}
+
+// validateText validates that the optional text has no surrounding whitespace and is within the length limit.
+func validateText(field, text string, maxLength int) []error {
+	var errors []error
+	if strings.TrimSpace(text) != text {
+		errors = append(errors, fmt.Errorf("%s must not start or end with whitespace", field))
+	}
+	if n := utf8.RuneCountInString(text); n > maxLength {
+		errors = append(errors, fmt.Errorf("%s is %d characters, the limit is %d", field, n, maxLength))
+	}
+	return errors
+}
+
+// locales returns the locales of the translations, sorted.
+func (tm *tokenManager) locales() []string {
+	set := make(map[string]struct{})
+	for _, localizations := range tm.localizations {
+		for locale := range localizations {
+			if models.IsValidLocale(locale) && locale != models.DefaultLocale {
+				set[locale] = struct{}{}
+			}
+		}
+	}
+	return sortedKeys(set)
+}
+
+// writeLocalizations writes :locale/tokens.json for every locale, the names and the descriptions
+// of the tokens are translated, falling back to the default locale.
+func (tm *tokenManager) writeLocalizations(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	for _, locale := range tm.locales() {
+		list := []models.Token{}
+		for _, tokenUid := range sortedTokenUids(tokens) {
+			token := *tokens[tokenUid]
+			localization := tm.localizations[tokenUid][locale]
+			if localization.Name != "" {
+				token.Name = localization.Name
+			}
+			if localization.Description != "" {
+				token.Description = localization.Description
+			}
+			list = append(list, token)
+		}
+		err := w.writeJSON(path.Join(locale, "tokens.json"), list)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// I18nCoverage returns the translation coverage of the listed tokens for every locale.
+func (tm *tokenManager) I18nCoverage(ctx context.Context) *models.I18nCoverage {
+	tokens := tm.publishedTokens(ProfileAll)
+	coverage := &models.I18nCoverage{Tokens: len(tokens), Locales: []models.LocaleCoverage{}}
+	for _, locale := range tm.locales() {
+		c := models.LocaleCoverage{Locale: locale, Missing: []models.MissingTranslation{}}
+		for _, tokenUid := range sortedTokenUids(tokens) {
+			localization := tm.localizations[tokenUid][locale]
+			var missing []string
+			if localization.Name != "" {
+				c.Names++
+			} else {
+				missing = append(missing, "name")
+			}
+			if localization.Description != "" {
+				c.Descriptions++
+			} else {
+				missing = append(missing, "description")
+			}
+			if len(missing) > 0 {
+				c.Missing = append(c.Missing, models.MissingTranslation{
+					TokenUuid: tokenUid,
+					Symbol:    tokens[tokenUid].Symbol,
+					Fields:    missing,
+				})
+			}
+		}
+		coverage.Locales = append(coverage.Locales, c)
+	}
+	return coverage
+}
+
+// sortedKeys returns the keys of the map, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,95 @@
+package tokenmanager
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/ma3xco/token-listing/internal/confusable"
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// findLookalikes reports the tokens whose symbol or name looks the same as the symbol or name
+// of the token, after the Unicode normalization, the confusable mapping and the case folding.
+// a collision with a featured, stable or blue-checkmark token is an error, unless the token
+// itself is featured, other collisions are warnings.
+// the tokens that list each other in same_asset_as are allowed to collide.
+func (tm *tokenManager) findLookalikes(tokenUid string) []error {
+	token := tm.tokens[tokenUid]
+	symbol := confusable.Skeleton(token.Symbol)
+	name := confusable.Skeleton(token.Name)
+	_, featured := tm.featuredTokens[tokenUid]
+
+	otherUids := make([]string, 0, len(tm.tokens))
+	for otherUid := range tm.tokens {
+		if otherUid != tokenUid {
+			otherUids = append(otherUids, otherUid)
+		}
+	}
+	sort.Strings(otherUids)
+
+	var findings []error
+	for _, otherUid := range otherUids {
+		other := tm.tokens[otherUid]
+		if tm.isSameAsset(tokenUid, otherUid) {
+			continue
+		}
+		var field string
+		switch {
+		case symbol != "" && symbol == confusable.Skeleton(other.Symbol):
+			field = fmt.Sprintf("symbol %q looks like the symbol %q", token.Symbol, other.Symbol)
+		case name != "" && name == confusable.Skeleton(other.Name):
+			field = fmt.Sprintf("name %q looks like the name %q", token.Name, other.Name)
+		default:
+			continue
+		}
+		if reason := tm.protectedReason(otherUid); reason != "" && !featured {
+			findings = append(findings, fmt.Errorf("%s of the %s token %s (%s), list each other in same_asset_as if it is the same asset",
+				field, reason, other.Symbol, otherUid))
+		} else {
+			findings = append(findings, warningf("%s of the token %s (%s), list each other in same_asset_as if it is the same asset",
+				field, other.Symbol, otherUid))
+		}
+	}
+	return findings
+}
+
+// validateSameAsset validates that the same_asset_as tokens exist and list the token back.
+func (tm *tokenManager) validateSameAsset(tokenUid string) []error {
+	var errors []error
+	for _, otherUid := range tm.tokens[tokenUid].SameAssetAs {
+		other, ok := tm.tokens[otherUid]
+		switch {
+		case otherUid == tokenUid:
+			errors = append(errors, fmt.Errorf("same_asset_as must not contain the token itself"))
+		case !ok:
+			errors = append(errors, fmt.Errorf("same_asset_as token '%s' does not exist", otherUid))
+		case !slices.Contains(other.SameAssetAs, tokenUid):
+			errors = append(errors, fmt.Errorf("same_asset_as token '%s' does not list this token back", otherUid))
+		}
+	}
+	return errors
+}
+
+// isSameAsset reports whether the tokens list each other in same_asset_as.
+// a token listing a featured token on its own is not enough, otherwise anyone could allow the impersonation.
+func (tm *tokenManager) isSameAsset(tokenUid, otherUid string) bool {
+	return slices.Contains(tm.tokens[tokenUid].SameAssetAs, otherUid) &&
+		slices.Contains(tm.tokens[otherUid].SameAssetAs, tokenUid)
+}
+
+// protectedReason returns why the token is protected against the lookalikes
+// (featured, stable or blue-checkmark), or empty if it is not.
+func (tm *tokenManager) protectedReason(tokenUid string) string {
+	if _, ok := tm.featuredTokens[tokenUid]; ok {
+		return "featured"
+	}
+	token := tm.tokens[tokenUid]
+	if token.IsStableToken {
+		return "stable"
+	}
+	if slices.ContainsFunc(token.Addresses, func(address models.TokenAddress) bool { return address.HasBlueCheckmark }) {
+		return "blue-checkmark"
+	}
+	return ""
+}
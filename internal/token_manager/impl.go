@@ -2,15 +2,71 @@ package tokenmanager
 
 import (
 	"context"
+	"crypto/ed25519"
 	"regexp"
+	"time"
 
+	"github.com/ma3xco/token-listing/internal/imaging"
 	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/ma3xco/token-listing/internal/onchain"
+	"github.com/ma3xco/token-listing/internal/pricefeed"
 	"github.com/sirupsen/logrus"
 )
 
 type tokenManager struct {
 	logger logrus.FieldLogger
 
+	// Config -------------------------------------------------------------
+
+	// the directory the build assets are published into.
+	distDir string
+
+	// the base URL the dist directory is deployed to, the published logo URLs point at it.
+	cdnBaseUrl string
+
+	// the transparent border of a 64x64 logo above which the logo gets a warning,
+	// scaled with the logo size.
+	maxLogoPadding int
+
+	// the hash distance up to which two logos are considered the same.
+	maxLogoHashDistance int
+
+	// the registry version written into the manifest, 0 means resolved from git.
+	registryVersion int64
+
+	// the registry version of the published build (e.g., the deployed manifest.json),
+	// the build fails with a lower version, 0 means only the dist directory is checked.
+	publishedRegistryVersion int64
+
+	// the source commit written into the manifest, empty means resolved from git.
+	sourceCommit string
+
+	// the build time written into the manifest, zero means the current time.
+	buildTime time.Time
+
+	// the key the manifest is signed with, nil means the build is not signed.
+	// it is never needed for the validation.
+	signingKey ed25519.PrivateKey
+
+	// whether every artifact is signed, not only the manifest.
+	signArtifacts bool
+
+	// the tokens of the previous registry state, the changelog is built against them.
+	// nil means no changelog is built.
+	previousTokens []models.Token
+
+	// the label of the previous registry state written into the changelog.
+	previousLabel string
+
+	// the build profiles, every profile is published into its own tree.
+	profiles []Profile
+
+	// the checker of the live price urls, nil means the urls are not fetched.
+	priceChecker *pricefeed.Checker
+
+	// the verifier of the addresses against their contracts, nil means the contracts are not read.
+	onchainVerifier *onchain.Verifier
+
 	// State --------------------------------------------------------------
 
 	// the key is the network id, the value is the network.
@@ -25,12 +81,34 @@ type tokenManager struct {
 	// list of featured tokens, the key is the token uid, the value is the token.
 	featuredTokens map[string]struct{}
 
+	// list of tokens with a logo.svg, the key is the token uid.
+	svgLogos map[string]struct{}
+
+	// list of networks with an icon.svg, the key is the network id.
+	svgNetworkIcons map[int64]struct{}
+
+	// the perceptual hashes of the logo.png, the key is the token uid.
+	logoHashes map[string]imaging.Hash
+
+	// the translations, the key is the token uid, the value is the translations by the locale.
+	localizations map[string]map[string]models.Localization
+
+	// the tag definitions in the order of tags/tags.json.
+	tagDefinitions []models.Tag
+
+	// the key is the tag id, the value is the tag definition.
+	tags map[string]models.Tag
+
 	// the key is the coin marketcap id, the value is the token uid.
 	// if the value is empty, then the token is not on CoinMarketCap.
 	coinMarketcapIdToTokenUid map[int64]string
 
 	// the key is the network id, the value is the token address map to the token uid.
 	networkTokenAddresses map[int64]map[string]string
+
+	// the key is the network id, the value is the canonical token address map to the token uid.
+	// refer to models.Network.CanonicalAddress.
+	canonicalTokenAddresses map[int64]map[string]string
 }
 
 var _ ITokenManager = (*tokenManager)(nil)
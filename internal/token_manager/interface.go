@@ -2,6 +2,9 @@ package tokenmanager
 
 import (
 	"context"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/ma3xco/token-listing/pkg/search"
 )
 
 // ITokenManager is the interface for the token manager.
@@ -21,8 +24,20 @@ type ITokenManager interface {
 	// ValidateTokens validates the tokens in the memory.
 	// it returns an error if any.
 	// the map key is the token uid, the value is the errors.
+	// the errors with the warning severity do not fail the validation, refer to IsWarning.
 	ValidateTokens(ctx context.Context) map[string][]error
 
+	// ValidateNetworks validates the networks in the memory.
+	// it returns an error if any.
+	// the map key is the network id, the value is the errors.
+	// the errors with the warning severity do not fail the validation, refer to IsWarning.
+	ValidateNetworks(ctx context.Context) map[int64][]error
+
+	// ValidateTags validates the tag definitions in the memory.
+	// it returns an error if any.
+	// the map key is the tag id, the value is the errors.
+	ValidateTags(ctx context.Context) map[string][]error
+
 	// ValidateTokensForFork validates tokens with fork-specific rules.
 	// Fork tokens must have:
 	// - order_index >= 100000
@@ -46,6 +61,31 @@ type ITokenManager interface {
 	// the map key is the token uid, the value is the errors.
 	ValidateTokensForForkByUids(ctx context.Context, tokenUids []string) map[string][]error
 
+	// GetToken returns a copy of the listed token with the uid,
+	// without the delisted addresses and the addresses on the inactive networks.
+	// it returns false if the token is not found, disabled, a scam or delisted.
+	GetToken(ctx context.Context, uid string) (*models.Token, bool)
+
+	// GetTokenByAddress returns a copy of the listed token with the address on the network.
+	// the address is canonicalized, refer to models.Network.CanonicalAddress.
+	// it returns false if the network or the token is not found, or the address is not listed, refer to GetToken.
+	GetTokenByAddress(ctx context.Context, networkId int64, address string) (*models.Token, bool)
+
+	// ListTokens returns the copies of the listed tokens matching the filter, refer to GetToken,
+	// sorted by the order index and then by the uid.
+	ListTokens(ctx context.Context, filter TokenFilter) []models.Token
+
+	// ListNetworks returns the networks sorted by the id.
+	ListNetworks(ctx context.Context) []models.Network
+
+	// SearchIndex returns the search index of the listed tokens in the memory,
+	// the same index the build publishes as search_index.json at the root of the dist directory.
+	SearchIndex(ctx context.Context) *search.Index
+
+	// I18nCoverage returns the translation coverage of the listed tokens for every locale,
+	// with the tokens missing a translated name or description.
+	I18nCoverage(ctx context.Context) *models.I18nCoverage
+
 	// BuildTokens builds the tokens in the memory.into ./dist/***
 	// the build assets contains
 	// - tokens.json (all tokens list)
@@ -54,6 +94,11 @@ type ITokenManager interface {
 	// - tokens/:tokenUid.json (the token Hashmap)
 	// - :coin_marketcap_id.json (the coin marketcap Hashmap)
 	// - tokens.featured.json (the featured tokens list)
+	// - tags.json & tags/:tag.json (the tag definitions and the tokens of every tag)
+	// - :locale/tokens.json (the tokens list translated into the locale, falling back to english)
+	// every build profile is published into its own tree, refer to WithProfiles.
+	// the assets are built into a staging directory and swapped into place once verified,
+	// on error the previous build is left untouched.
 	// it returns an error if any.
 	BuildTokens(ctx context.Context) error
 }
@@ -0,0 +1,181 @@
+package tokenmanager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// migrationsPath is the path of the migration map relative to the dist directory.
+const migrationsPath = "migrations.json"
+
+// maxSuccessorChain is the number of the successors followed before a chain is reported as a cycle.
+const maxSuccessorChain = 16
+
+// addressLifecycle returns the lifecycle of the token address,
+// the lifecycle of the address overrides the lifecycle of the token.
+func addressLifecycle(token *models.Token, address models.TokenAddress) *models.Lifecycle {
+	if address.Lifecycle != nil {
+		return address.Lifecycle
+	}
+	return token.Lifecycle
+}
+
+// resolveAddressRef returns the token uid and the address the reference refers to,
+// the address is matched canonically, refer to models.Network.CanonicalAddress.
+// it returns false if the reference is not an address of the registry.
+func (tm *tokenManager) resolveAddressRef(ref models.AddressRef) (string, models.TokenAddress, bool) {
+	network, ok := tm.networks[int64(ref.NetworkId)]
+	if !ok {
+		return "", models.TokenAddress{}, false
+	}
+	canonical := network.CanonicalAddress(ref.Address)
+	uid, ok := tm.canonicalTokenAddresses[network.Id][canonical]
+	if !ok {
+		return "", models.TokenAddress{}, false
+	}
+	for _, address := range tm.tokens[uid].Addresses {
+		if int64(address.NetworkId) == network.Id && network.CanonicalAddress(address.Address) == canonical {
+			return uid, address, true
+		}
+	}
+	return "", models.TokenAddress{}, false
+}
+
+// validateLifecycle validates the lifecycle of the token and of its addresses:
+// - the lifecycle fields are consistent, refer to models.Lifecycle.Validate.
+// - the successor is an address of the registry, other than the address itself.
+// - the chain of the successors ends at an active or deprecated address, without a cycle.
+// - no successor belongs to a disabled or scam token.
+func (tm *tokenManager) validateLifecycle(tokenUid string) []error {
+	token := tm.tokens[tokenUid]
+	var errors []error
+	if err := token.Lifecycle.Validate(); err != nil {
+		errors = append(errors, fmt.Errorf("lifecycle: %v", err))
+	}
+	for i, address := range token.Addresses {
+		if err := address.Lifecycle.Validate(); err != nil {
+			errors = append(errors, fmt.Errorf("address[%d]: lifecycle: %v", i, err))
+		}
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+
+	for i, address := range token.Addresses {
+		lifecycle := addressLifecycle(token, address)
+		if lifecycle == nil || lifecycle.Successor == nil {
+			continue
+		}
+		if err := tm.validateSuccessorChain(address, lifecycle.Successor); err != nil {
+			errors = append(errors, fmt.Errorf("address[%d]: lifecycle: %v", i, err))
+		}
+	}
+	return errors
+}
+
+// validateSuccessorChain follows the successors of the address until an active or deprecated address,
+// the successors must belong to listed tokens, the users are never pointed at a disabled or scam token.
+func (tm *tokenManager) validateSuccessorChain(address models.TokenAddress, successor *models.AddressRef) error {
+	visited := map[string]struct{}{fmt.Sprintf("%d/%s", address.NetworkId, address.Address): {}}
+	for range maxSuccessorChain {
+		nextUid, nextAddress, ok := tm.resolveAddressRef(*successor)
+		if !ok {
+			return fmt.Errorf("successor %d/%s is not an address of the registry", successor.NetworkId, successor.Address)
+		}
+		key := fmt.Sprintf("%d/%s", nextAddress.NetworkId, nextAddress.Address)
+		if _, ok := visited[key]; ok {
+			return fmt.Errorf("successor %d/%s leads back to a predecessor", successor.NetworkId, successor.Address)
+		}
+		visited[key] = struct{}{}
+		if reason := blockReason(*tm.tokens[nextUid]); reason != "" {
+			return fmt.Errorf("successor %d/%s belongs to the %s token %s", successor.NetworkId, successor.Address, reason, nextUid)
+		}
+		lifecycle := addressLifecycle(tm.tokens[nextUid], nextAddress)
+		switch lifecycle.EffectiveStatus() {
+		case models.LifecycleStatus_ACTIVE, models.LifecycleStatus_DEPRECATED:
+			if lifecycle != nil && lifecycle.Successor != nil {
+				successor = lifecycle.Successor
+				continue
+			}
+			return nil
+		case models.LifecycleStatus_DELISTED:
+			return fmt.Errorf("successor %d/%s is delisted", successor.NetworkId, successor.Address)
+		default:
+			if lifecycle.Successor == nil {
+				return fmt.Errorf("successor %d/%s is migrated without a successor", successor.NetworkId, successor.Address)
+			}
+			successor = lifecycle.Successor
+		}
+	}
+	return fmt.Errorf("successor chain is longer than %d addresses", maxSuccessorChain)
+}
+
+// writeMigrations writes migrations.json with the published addresses that have a successor,
+// the successors must be published by the profile as well, the other migrations are skipped with a warning.
+func (tm *tokenManager) writeMigrations(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	migrations := models.Migrations{Networks: make(map[int64][]models.Migration)}
+	for _, tokenUid := range sortedTokenUids(tokens) {
+		token := tokens[tokenUid]
+		for _, address := range token.Addresses {
+			lifecycle := addressLifecycle(token, address)
+			if lifecycle == nil || lifecycle.Successor == nil {
+				continue
+			}
+			successorUid, successorAddress, ok := tm.resolveAddressRef(*lifecycle.Successor)
+			if !ok {
+				return fmt.Errorf("token %s: successor %d/%s not found", tokenUid, lifecycle.Successor.NetworkId, lifecycle.Successor.Address)
+			}
+			if !tm.isPublishedAddress(tokens, successorUid, successorAddress) {
+				tm.logger.Warnf("token %s: successor %d/%s is not published by the %s profile, the migration is skipped",
+					tokenUid, lifecycle.Successor.NetworkId, lifecycle.Successor.Address, w.profile)
+				continue
+			}
+			network := tm.networks[int64(address.NetworkId)]
+			successorNetwork := tm.networks[int64(successorAddress.NetworkId)]
+			successorCaip19, err := successorNetwork.CAIP19(successorAddress)
+			if err != nil {
+				return fmt.Errorf("token %s: %w", successorUid, err)
+			}
+			migrations.Networks[network.Id] = append(migrations.Networks[network.Id], models.Migration{
+				Address:   network.CanonicalAddress(address.Address),
+				Caip19:    address.Caip19,
+				TokenUuid: tokenUid,
+				Symbol:    token.Symbol,
+				Status:    lifecycle.Status,
+				Since:     lifecycle.Since,
+				Reason:    lifecycle.Reason,
+				Successor: models.MigrationTarget{
+					NetworkId: successorAddress.NetworkId,
+					Address:   successorNetwork.CanonicalAddress(successorAddress.Address),
+					Caip19:    successorCaip19,
+					TokenUuid: successorUid,
+					Symbol:    tm.tokens[successorUid].Symbol,
+				},
+			})
+		}
+	}
+	for _, entries := range migrations.Networks {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Address < entries[j].Address
+		})
+	}
+	return w.writeJSON(migrationsPath, migrations)
+}
+
+// isPublishedAddress reports whether the address of the token is among the published tokens.
+func (tm *tokenManager) isPublishedAddress(tokens map[string]*models.Token, tokenUid string, address models.TokenAddress) bool {
+	token, ok := tokens[tokenUid]
+	if !ok {
+		return false
+	}
+	network := tm.networks[int64(address.NetworkId)]
+	for _, published := range token.Addresses {
+		if published.NetworkId == address.NetworkId && network.CanonicalAddress(published.Address) == network.CanonicalAddress(address.Address) {
+			return true
+		}
+	}
+	return false
+}
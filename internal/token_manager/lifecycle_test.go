@@ -0,0 +1,117 @@
+package tokenmanager
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// newLifecycleTestManager returns a token manager with an old token migrated to a new token on network 2.
+func newLifecycleTestManager() *tokenManager {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	tm := &tokenManager{
+		logger: logger,
+		networks: map[int64]models.Network{
+			2: {Id: 2, NetworkType: models.NetworkType_NETWORK_TYPE_ETH_LIKE, ChainId: 1, CoinType: models.Coin_TYPE_ETH, IsActive: true},
+			6: {Id: 6, NetworkType: models.NetworkType_NETWORK_TYPE_ETH_LIKE, ChainId: 11155111, CoinType: models.Coin_TYPE_ETH, IsActive: true, IsTestnet: true},
+		},
+		tokens: map[string]*models.Token{
+			"old": {Uuid: "old", Symbol: "OLD", OrderIndex: 1, Addresses: []models.TokenAddress{{
+				NetworkId: 2, Address: "0x00000000000000000000000000000000000000aa", TokenType: "ERC20",
+				Lifecycle: &models.Lifecycle{
+					Status:    models.LifecycleStatus_MIGRATED,
+					Since:     "2026-01-01",
+					Reason:    "v2 token swap",
+					Successor: &models.AddressRef{NetworkId: 2, Address: "0x00000000000000000000000000000000000000BB"},
+				},
+			}}},
+			"new": {Uuid: "new", Symbol: "NEW", OrderIndex: 2, Addresses: []models.TokenAddress{{
+				NetworkId: 2, Address: "0x00000000000000000000000000000000000000bb", TokenType: "ERC20",
+			}}},
+		},
+		canonicalTokenAddresses: map[int64]map[string]string{
+			2: {
+				"0x00000000000000000000000000000000000000aa": "old",
+				"0x00000000000000000000000000000000000000bb": "new",
+			},
+		},
+	}
+	return tm
+}
+
+func TestValidateLifecycleSuccessor(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(successor *models.Token)
+		wantErr string
+	}{
+		{"listed successor", func(successor *models.Token) {}, ""},
+		{"disabled successor", func(successor *models.Token) { successor.IsDisabled = true }, "belongs to the disabled token new"},
+		{"scam successor", func(successor *models.Token) { successor.IsScam = true }, "belongs to the scam token new"},
+		{"delisted successor", func(successor *models.Token) {
+			successor.Lifecycle = &models.Lifecycle{Status: models.LifecycleStatus_DELISTED, Since: "2026-01-01", Reason: "rug pull"}
+		}, "is delisted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := newLifecycleTestManager()
+			tt.modify(tm.tokens["new"])
+			errs := tm.validateLifecycle("old")
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("validateLifecycle() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Errorf("validateLifecycle() = %v, want an error containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteMigrationsSkipsUnpublishedSuccessors(t *testing.T) {
+	readMigrations := func(t *testing.T, tm *tokenManager, tokens map[string]*models.Token) models.Migrations {
+		t.Helper()
+		w := newDistWriter(t.TempDir(), ProfileMainnet)
+		if err := tm.writeMigrations(context.Background(), w, tokens); err != nil {
+			t.Fatalf("writeMigrations() error = %v", err)
+		}
+		bytes, err := os.ReadFile(filepath.Join(w.root, migrationsPath))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var migrations models.Migrations
+		if err := json.Unmarshal(bytes, &migrations); err != nil {
+			t.Fatal(err)
+		}
+		return migrations
+	}
+
+	tm := newLifecycleTestManager()
+	migrations := readMigrations(t, tm, tm.publishedTokens(ProfileMainnet))
+	if entries := migrations.Networks[2]; len(entries) != 1 || entries[0].Successor.TokenUuid != "new" {
+		t.Errorf("migrations = %+v, want the migration of old to new", migrations.Networks)
+	}
+
+	// the successor on a testnet is not published by the mainnet profile.
+	tm = newLifecycleTestManager()
+	tm.tokens["new"].Addresses[0].NetworkId = 6
+	tm.canonicalTokenAddresses = map[int64]map[string]string{
+		2: {"0x00000000000000000000000000000000000000aa": "old"},
+		6: {"0x00000000000000000000000000000000000000bb": "new"},
+	}
+	tm.tokens["old"].Addresses[0].Lifecycle.Successor.NetworkId = 6
+	migrations = readMigrations(t, tm, tm.publishedTokens(ProfileMainnet))
+	if len(migrations.Networks) != 0 {
+		t.Errorf("migrations = %+v, want the migration to the unpublished successor skipped", migrations.Networks)
+	}
+}
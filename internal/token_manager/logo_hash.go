@@ -0,0 +1,108 @@
+package tokenmanager
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"sort"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/imaging"
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+const (
+	// logoHashesPath is the path of the logo hashes in the dist directory.
+	logoHashesPath = "logo_hashes.json"
+
+	// logoHashAlgorithm is the name of the hash algorithm written into the logo hashes.
+	logoHashAlgorithm = "dhash-256"
+)
+
+// findSimilarLogos reports a warning when the logo of the token is within the max logo
+// hash distance of the logo of a token with a different symbol or of a featured token.
+// the featured tokens are curated, so they never get the warning, the tokens that wrap
+// each other are allowed to share the logo.
+func (tm *tokenManager) findSimilarLogos(tokenUid string) []error {
+	hash, ok := tm.logoHashes[tokenUid]
+	if !ok {
+		return nil
+	}
+	token := tm.tokens[tokenUid]
+	if _, ok := tm.featuredTokens[tokenUid]; ok {
+		return nil
+	}
+
+	type match struct {
+		uid      string
+		distance int
+	}
+	var matches []match
+	for otherUid, otherHash := range tm.logoHashes {
+		if otherUid == tokenUid {
+			continue
+		}
+		other := tm.tokens[otherUid]
+		if token.WrappedTokenUuid == otherUid || other.WrappedTokenUuid == tokenUid {
+			continue
+		}
+		_, featured := tm.featuredTokens[otherUid]
+		if !featured && strings.EqualFold(token.Symbol, other.Symbol) {
+			continue
+		}
+		distance := hash.Distance(otherHash)
+		if distance <= tm.maxLogoHashDistance {
+			matches = append(matches, match{uid: otherUid, distance: distance})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].uid < matches[j].uid
+	})
+
+	var findings []error
+	for _, m := range matches {
+		other := tm.tokens[m.uid]
+		kind := "token"
+		if _, ok := tm.featuredTokens[m.uid]; ok {
+			kind = "featured token"
+		}
+		findings = append(findings, warningf("logo looks like the logo of the %s %s (%s), hash distance: %d",
+			kind, other.Symbol, m.uid, m.distance))
+	}
+	return findings
+}
+
+// writeLogoHashes writes the logo hashes of the published tokens using the dist writer.
+func (tm *tokenManager) writeLogoHashes(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	logoHashes := models.LogoHashes{
+		Algorithm:   logoHashAlgorithm,
+		MaxDistance: tm.maxLogoHashDistance,
+		Hashes:      []models.LogoHash{},
+	}
+	for _, tokenUid := range sortedTokenUids(tokens) {
+		hash, ok := tm.logoHashes[tokenUid]
+		if !ok {
+			continue
+		}
+		_, featured := tm.featuredTokens[tokenUid]
+		logoHashes.Hashes = append(logoHashes.Hashes, models.LogoHash{
+			TokenUuid:  tokenUid,
+			Symbol:     tokens[tokenUid].Symbol,
+			IsFeatured: featured,
+			Hash:       hash.String(),
+		})
+	}
+	return w.writeJSON(logoHashesPath, logoHashes)
+}
+
+// hashLogo returns the difference hash of the decoded logo.
+func hashLogo(logo []byte) (imaging.Hash, error) {
+	img, err := png.Decode(bytes.NewReader(logo))
+	if err != nil {
+		return imaging.Hash{}, err
+	}
+	return imaging.DifferenceHash(img), nil
+}
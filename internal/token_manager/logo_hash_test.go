@@ -0,0 +1,114 @@
+package tokenmanager
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// coinLogo returns a 64x64 coin logo: a disc of the color with a bar across it, shifted by dx, dy.
+func coinLogo(c color.NRGBA, dx, dy int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			cx, cy := 2*(x-dx)+1-64, 2*(y-dy)+1-64
+			switch {
+			case cx*cx+cy*cy > 4*28*28:
+			case cy > -12 && cy < 12 && cx > -36 && cx < 36:
+				img.SetNRGBA(x, y, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+			default:
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+// stripesLogo returns a 64x64 logo of diagonal stripes.
+func stripesLogo() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if (x+y)/8%2 == 0 {
+				img.SetNRGBA(x, y, color.NRGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xff})
+			}
+		}
+	}
+	return img
+}
+
+func TestFindSimilarLogos(t *testing.T) {
+	green := color.NRGBA{R: 0x26, G: 0xa1, B: 0x7b, A: 0xff}
+	blue := color.NRGBA{R: 0x27, G: 0x75, B: 0xca, A: 0xff}
+	logos := map[string]image.Image{
+		"original":  coinLogo(green, 0, 0),
+		"recolored": coinLogo(blue, 0, 0),
+		"shifted":   coinLogo(green, 1, 1),
+		"unrelated": stripesLogo(),
+		"same":      coinLogo(blue, 0, 0),
+	}
+	symbols := map[string]string{
+		"original":  "ORIG",
+		"recolored": "RCLR",
+		"shifted":   "SHFT",
+		"unrelated": "UNRL",
+		"same":      "orig",
+	}
+	tm := &tokenManager{}
+	tm.setDefaults()
+	for uid, logo := range logos {
+		hash, err := hashLogo(encodePNG(t, logo))
+		if err != nil {
+			t.Fatalf("hashLogo(%s) error = %v", uid, err)
+		}
+		tm.logoHashes[uid] = hash
+		tm.tokens[uid] = &models.Token{Uuid: uid, Symbol: symbols[uid]}
+	}
+
+	tests := []struct {
+		uid  string
+		want []string
+	}{
+		{"recolored", []string{
+			"logo looks like the logo of the token ORIG (original), hash distance: 0",
+			"logo looks like the logo of the token orig (same), hash distance: 0",
+			"logo looks like the logo of the token SHFT (shifted), hash distance: 7",
+		}},
+		{"shifted", []string{
+			"logo looks like the logo of the token ORIG (original), hash distance: 7",
+			"logo looks like the logo of the token RCLR (recolored), hash distance: 7",
+			"logo looks like the logo of the token orig (same), hash distance: 7",
+		}},
+		{"unrelated", nil},
+		// the tokens with the same symbol (in any case) may share the logo.
+		{"same", []string{
+			"logo looks like the logo of the token RCLR (recolored), hash distance: 0",
+			"logo looks like the logo of the token SHFT (shifted), hash distance: 7",
+		}},
+		{"unknown", nil},
+	}
+	for _, tt := range tests {
+		findings := tm.findSimilarLogos(tt.uid)
+		if len(findings) != len(tt.want) {
+			t.Errorf("findSimilarLogos(%s) = %v, want %q", tt.uid, findings, tt.want)
+			continue
+		}
+		for i, finding := range findings {
+			if finding.Error() != tt.want[i] || !IsWarning(finding) {
+				t.Errorf("findSimilarLogos(%s)[%d] = %v, want the warning %q", tt.uid, i, finding, tt.want[i])
+			}
+		}
+	}
+
+	// the featured tokens never get the warning, the others are warned against them regardless of the symbol.
+	tm.featuredTokens["original"] = struct{}{}
+	if findings := tm.findSimilarLogos("original"); len(findings) != 0 {
+		t.Errorf("findSimilarLogos() of a featured token = %v, want none", findings)
+	}
+	findings := tm.findSimilarLogos("same")
+	if len(findings) != 3 || findings[0].Error() != "logo looks like the logo of the featured token ORIG (original), hash distance: 0" {
+		t.Errorf("findSimilarLogos() = %v, want the featured token with the same symbol first", findings)
+	}
+}
@@ -0,0 +1,142 @@
+package tokenmanager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+)
+
+// PNG color types with an alpha channel and with a palette, refer to the IHDR chunk of the PNG specification.
+const (
+	pngColorTypePalette   = 3
+	pngColorTypeGrayAlpha = 4
+	pngColorTypeRGBA      = 6
+)
+
+const (
+	// visibleAlpha is the alpha above which a pixel counts as visible.
+	visibleAlpha = 16
+
+	// minVisibleRatio is the ratio of the visible pixels below which the logo is nearly blank.
+	minVisibleRatio = 0.05
+
+	// minColorDeviation is the standard deviation of the visible pixel colors
+	// below which the logo is a near-uniform color.
+	minColorDeviation = 4.0
+)
+
+// analyzeLogo decodes the logo png and reports the quality issues as warnings:
+// - no alpha channel, or an alpha channel with fully opaque pixels only.
+// - a transparent border wider than the max logo padding (relative to 64x64).
+// - nearly blank (almost no visible pixels).
+// - near-uniform color.
+// - a palette image instead of a 32-bit RGBA image.
+func (tm *tokenManager) analyzeLogo(logoPath string) []error {
+	content, err := os.ReadFile(logoPath)
+	if err != nil {
+		return []error{fmt.Errorf("cannot read logo file: %v", err)}
+	}
+	colorType, hasTransparency, err := pngColorInfo(content)
+	if err != nil {
+		return []error{err}
+	}
+	img, err := png.Decode(bytes.NewReader(content))
+	if err != nil {
+		return []error{fmt.Errorf("cannot decode PNG: %v", err)}
+	}
+
+	var findings []error
+	hasAlpha := colorType == pngColorTypeGrayAlpha || colorType == pngColorTypeRGBA || hasTransparency
+	if colorType == pngColorTypePalette {
+		findings = append(findings, warningf("logo uses a palette color type, a 32-bit RGBA PNG is expected"))
+	}
+	if !hasAlpha {
+		findings = append(findings, warningf("logo has no alpha channel, a transparent background is expected"))
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	visible := image.Rectangle{}
+	visibleCount := 0
+	opaque := true
+	var sum, sumSq [3]float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A != 0xff {
+				opaque = false
+			}
+			if c.A <= visibleAlpha {
+				continue
+			}
+			visibleCount++
+			visible = visible.Union(image.Rect(x, y, x+1, y+1))
+			for i, v := range [3]uint8{c.R, c.G, c.B} {
+				sum[i] += float64(v)
+				sumSq[i] += float64(v) * float64(v)
+			}
+		}
+	}
+
+	if opaque && hasAlpha {
+		findings = append(findings, warningf("logo is fully opaque, a transparent background is expected"))
+	}
+	if float64(visibleCount) < minVisibleRatio*float64(width*height) {
+		findings = append(findings, warningf("logo is nearly blank: %d of %d pixels are visible", visibleCount, width*height))
+		return findings
+	}
+
+	padding := min(
+		visible.Min.X-bounds.Min.X,
+		visible.Min.Y-bounds.Min.Y,
+		bounds.Max.X-visible.Max.X,
+		bounds.Max.Y-visible.Max.Y,
+	)
+	maxPadding := tm.maxLogoPadding * width / 64
+	if padding > maxPadding {
+		findings = append(findings, warningf("logo has a transparent border of %d pixels (max: %d pixels at %dx%d)", padding, maxPadding, width, height))
+	}
+
+	deviation := 0.0
+	for i := range sum {
+		mean := sum[i] / float64(visibleCount)
+		deviation = math.Max(deviation, math.Sqrt(math.Max(0, sumSq[i]/float64(visibleCount)-mean*mean)))
+	}
+	if deviation < minColorDeviation {
+		findings = append(findings, warningf("logo is a near-uniform color"))
+	}
+	return findings
+}
+
+// pngColorInfo returns the color type of the PNG from its IHDR chunk,
+// and whether it has a tRNS (transparency) chunk.
+func pngColorInfo(content []byte) (byte, bool, error) {
+	const signatureLen = 8
+	if len(content) < signatureLen+8+13 || string(content[12:16]) != "IHDR" {
+		return 0, false, fmt.Errorf("logo is not a valid PNG")
+	}
+	colorType := content[signatureLen+8+9]
+	hasTransparency := false
+	for offset := signatureLen; offset+8 <= len(content); {
+		length := binary.BigEndian.Uint32(content[offset:])
+		chunkType := string(content[offset+4 : offset+8])
+		// the chunk is the length, the type, the data and the CRC.
+		if uint64(length)+12 > uint64(len(content)-offset) {
+			return 0, false, fmt.Errorf("logo is not a valid PNG: %s chunk is truncated", chunkType)
+		}
+		if chunkType == "tRNS" {
+			hasTransparency = true
+		}
+		if chunkType == "IDAT" || chunkType == "IEND" {
+			// tRNS must precede the image data.
+			break
+		}
+		offset += 12 + int(length)
+	}
+	return colorType, hasTransparency, nil
+}
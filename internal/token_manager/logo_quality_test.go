@@ -0,0 +1,202 @@
+package tokenmanager
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// pngSignature is the signature every PNG starts with.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// pngChunk returns the chunk with its length and CRC.
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// rgbaPNG encodes the image as an 8-bit RGBA PNG (color type 6) even if it is opaque,
+// image/png writes the opaque images as RGB.
+func rgbaPNG(t *testing.T, img *image.NRGBA) []byte {
+	t.Helper()
+	bounds := img.Bounds()
+	ihdr := binary.BigEndian.AppendUint32(nil, uint32(bounds.Dx()))
+	ihdr = binary.BigEndian.AppendUint32(ihdr, uint32(bounds.Dy()))
+	ihdr = append(ihdr, 8, pngColorTypeRGBA, 0, 0, 0)
+	var idat bytes.Buffer
+	zw := zlib.NewWriter(&idat)
+	for y := 0; y < bounds.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+bounds.Dx()*4]
+		if _, err := zw.Write(append([]byte{0}, row...)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	content := []byte(pngSignature)
+	content = append(content, pngChunk("IHDR", ihdr)...)
+	content = append(content, pngChunk("IDAT", idat.Bytes())...)
+	return append(content, pngChunk("IEND", nil)...)
+}
+
+// encodePNG encodes the image with image/png.
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testLogo returns a 64x64 logo with a colored disc of the radius on the background.
+// a uniform disc has a single color, otherwise the color changes with the position.
+func testLogo(radius int, background color.NRGBA, uniform bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			c := background
+			if dx, dy := 2*x+1-64, 2*y+1-64; dx*dx+dy*dy <= 4*radius*radius {
+				c = color.NRGBA{R: 0x20, G: 0x80, B: 0xc0, A: 0xff}
+				if !uniform {
+					c.R, c.G = uint8(x*4), uint8(y*4)
+				}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestAnalyzeLogo(t *testing.T) {
+	transparent := color.NRGBA{}
+	white := color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+
+	// the palette logo has a transparent background in its tRNS chunk.
+	palette := image.NewPaletted(image.Rect(0, 0, 64, 64), color.Palette{transparent})
+	for i := 1; i < 256; i++ {
+		palette.Palette = append(palette.Palette, color.NRGBA{R: uint8(i), G: 0x80, B: uint8(255 - i), A: 0xff})
+	}
+	disc := testLogo(30, transparent, false)
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if disc.NRGBAAt(x, y).A != 0 {
+				palette.SetColorIndex(x, y, uint8(1+(x+y)%255))
+			}
+		}
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i % 64 * 4)
+	}
+
+	dot := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	dot.SetNRGBA(32, 32, color.NRGBA{R: 0xff, A: 0xff})
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    []string
+	}{
+		{"transparent RGBA", encodePNG(t, testLogo(30, transparent, false)), nil},
+		{"palette", encodePNG(t, palette), []string{"palette color type"}},
+		{"grayscale", encodePNG(t, gray), []string{"no alpha channel"}},
+		{"opaque RGB", encodePNG(t, testLogo(30, white, false)), []string{"no alpha channel"}},
+		{"opaque RGBA", rgbaPNG(t, testLogo(30, white, false)), []string{"fully opaque"}},
+		{"wide border", encodePNG(t, testLogo(16, transparent, false)), []string{"transparent border of 16 pixels (max: 8 pixels at 64x64)"}},
+		{"nearly blank", encodePNG(t, dot), []string{"nearly blank: 1 of 4096 pixels are visible"}},
+		{"uniform", encodePNG(t, testLogo(30, transparent, true)), []string{"near-uniform color"}},
+	}
+	tm := &tokenManager{}
+	tm.setDefaults()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logoPath := filepath.Join(t.TempDir(), "logo.png")
+			if err := os.WriteFile(logoPath, tt.content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			findings := tm.analyzeLogo(logoPath)
+			if len(findings) != len(tt.want) {
+				t.Fatalf("analyzeLogo() = %v, want %d findings containing %q", findings, len(tt.want), tt.want)
+			}
+			for i, finding := range findings {
+				if !strings.Contains(finding.Error(), tt.want[i]) || !IsWarning(finding) {
+					t.Errorf("analyzeLogo()[%d] = %v, want a warning containing %q", i, finding, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeLogoTruncated(t *testing.T) {
+	content := encodePNG(t, testLogo(30, color.NRGBA{}, false))
+	logoPath := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(logoPath, content[:len(content)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	tm := &tokenManager{}
+	tm.setDefaults()
+	findings := tm.analyzeLogo(logoPath)
+	if len(findings) != 1 || IsWarning(findings[0]) {
+		t.Errorf("analyzeLogo() of a truncated logo = %v, want an error", findings)
+	}
+}
+
+func TestPngColorInfo(t *testing.T) {
+	ihdr := func(colorType byte) []byte {
+		return pngChunk("IHDR", []byte{0, 0, 0, 1, 0, 0, 0, 1, 8, colorType, 0, 0, 0})
+	}
+	build := func(chunks ...[]byte) []byte {
+		return append([]byte(pngSignature), bytes.Join(chunks, nil)...)
+	}
+	// a chunk whose length runs past the end of the content.
+	oversized := binary.BigEndian.AppendUint32(nil, 1<<31)
+	oversized = append(oversized, "tEXt"...)
+
+	tests := []struct {
+		name             string
+		content          []byte
+		wantColorType    byte
+		wantTransparency bool
+		wantErr          string
+	}{
+		{"RGBA", build(ihdr(pngColorTypeRGBA), pngChunk("IDAT", []byte{1}), pngChunk("IEND", nil)), pngColorTypeRGBA, false, ""},
+		{"palette with tRNS", build(ihdr(pngColorTypePalette), pngChunk("PLTE", make([]byte, 6)), pngChunk("tRNS", []byte{0}), pngChunk("IDAT", []byte{1})), pngColorTypePalette, true, ""},
+		{"RGB with tRNS", build(ihdr(2), pngChunk("tRNS", make([]byte, 6)), pngChunk("IDAT", nil)), 2, true, ""},
+		{"grayscale", build(ihdr(0), pngChunk("IDAT", []byte{1})), 0, false, ""},
+		// tRNS after the image data is not valid and is ignored.
+		{"tRNS after IDAT", build(ihdr(2), pngChunk("IDAT", []byte{1}), pngChunk("tRNS", make([]byte, 6))), 2, false, ""},
+		{"truncated IHDR", []byte(pngSignature + "\x00\x00\x00\x0dIHDR\x00"), 0, false, "not a valid PNG"},
+		{"not a PNG", build(pngChunk("IDAT", make([]byte, 13))), 0, false, "not a valid PNG"},
+		{"truncated chunk", build(ihdr(2), pngChunk("tEXt", []byte("comment"))[:10]), 0, false, "tEXt chunk is truncated"},
+		{"chunk length past the end", build(ihdr(2), oversized), 0, false, "tEXt chunk is truncated"},
+		{"max chunk length", build(ihdr(2), append([]byte{0xff, 0xff, 0xff, 0xff}, "tEXt"...)), 0, false, "tEXt chunk is truncated"},
+		{"truncated IDAT", build(ihdr(2), pngChunk("IDAT", make([]byte, 16))[:20]), 0, false, "IDAT chunk is truncated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			colorType, hasTransparency, err := pngColorInfo(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("pngColorInfo() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || colorType != tt.wantColorType || hasTransparency != tt.wantTransparency {
+				t.Errorf("pngColorInfo() = %d, %v, %v, want %d, %v", colorType, hasTransparency, err, tt.wantColorType, tt.wantTransparency)
+			}
+		})
+	}
+}
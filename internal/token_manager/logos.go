@@ -0,0 +1,157 @@
+package tokenmanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/imaging"
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// logoSizes are the sizes of the square logo variants generated by the build.
+var logoSizes = []int{32, 64, 128, 256}
+
+// defaultLogoSize is the size of the logo published as tokens/:tokenUid.png and logo_png_url.
+const defaultLogoSize = 64
+
+const (
+	// minLogoSize is the minimum size of the token logo.png, the size of the largest variant.
+	// the smaller logos are accepted with a warning until their sources are replaced,
+	// the variants larger than the logo are not generated, refer to logoVariantSizes.
+	minLogoSize = 256
+
+	// minLegacyLogoSize and maxLogoSize are the bounds of the logo.png dimensions.
+	// minLegacyLogoSize is the default size, so every logo has the default variant.
+	minLegacyLogoSize = defaultLogoSize
+	maxLogoSize       = 1024
+)
+
+// logoVariantSizes returns the sizes of the variants generated from a logo of the size,
+// a variant larger than the logo would be an upscale.
+func logoVariantSizes(size int) []int {
+	var sizes []int
+	for _, variant := range logoSizes {
+		if variant <= size {
+			sizes = append(sizes, variant)
+		}
+	}
+	return sizes
+}
+
+// validateLogoSize reports a logo smaller than minLogoSize as a warning with the variants it misses.
+func (tm *tokenManager) validateLogoSize(logoPath string) error {
+	file, err := os.Open(logoPath)
+	if err != nil {
+		return fmt.Errorf("cannot open logo file: %v", err)
+	}
+	defer file.Close()
+	config, err := png.DecodeConfig(file)
+	if err != nil {
+		return fmt.Errorf("cannot decode PNG config: %v", err)
+	}
+	if config.Width >= minLogoSize {
+		return nil
+	}
+	var missing []string
+	for _, size := range logoSizes[len(logoVariantSizes(config.Width)):] {
+		missing = append(missing, strconv.Itoa(size))
+	}
+	return warningf("logo is %dx%d, smaller than %dx%d, the %s px variants are not generated",
+		config.Width, config.Height, minLogoSize, minLogoSize, strings.Join(missing, ", "))
+}
+
+// writeLogos generates the logo variants of the published tokens from their logo.png,
+// copies their logo.svg, if any, and points the logos, logo_png_url and logo_svg_url
+// of the tokens and of their addresses at the CDN copies, logo_svg_url is empty without a logo.svg.
+// every variant up to the logo size is written as tokens/:tokenUid/logo_:size.png and
+// tokens/:tokenUid/logo_:size.webp, the default size is also written as tokens/:tokenUid.png.
+func (tm *tokenManager) writeLogos(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	for _, tokenUid := range sortedTokenUids(tokens) {
+		token := tokens[tokenUid]
+		file, err := os.Open(fmt.Sprintf("tokens/%s/logo.png", tokenUid))
+		if err != nil {
+			return err
+		}
+		src, err := png.Decode(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("cannot decode the logo of token %s: %v", tokenUid, err)
+		}
+
+		sizes := logoVariantSizes(min(src.Bounds().Dx(), src.Bounds().Dy()))
+		logos := &models.LogoSet{
+			Png:  make(map[int]string, len(sizes)),
+			Webp: make(map[int]string, len(sizes)),
+		}
+		for _, size := range sizes {
+			img := imaging.Resize(src, size, size)
+
+			var pngBuf bytes.Buffer
+			err = png.Encode(&pngBuf, img)
+			if err != nil {
+				return err
+			}
+			pngPath := fmt.Sprintf("tokens/%s/logo_%d.png", tokenUid, size)
+			err = w.writeFile(pngPath, pngBuf.Bytes())
+			if err != nil {
+				return err
+			}
+			logos.Png[size] = tm.cdnUrl(w, pngPath)
+
+			var webpBuf bytes.Buffer
+			err = imaging.EncodeWebP(&webpBuf, img)
+			if err != nil {
+				return err
+			}
+			webpPath := fmt.Sprintf("tokens/%s/logo_%d.webp", tokenUid, size)
+			err = w.writeFile(webpPath, webpBuf.Bytes())
+			if err != nil {
+				return err
+			}
+			logos.Webp[size] = tm.cdnUrl(w, webpPath)
+
+			if size == defaultLogoSize {
+				defaultPath := fmt.Sprintf("tokens/%s.png", tokenUid)
+				err = w.writeFile(defaultPath, pngBuf.Bytes())
+				if err != nil {
+					return err
+				}
+				token.LogoPngUrl = tm.cdnUrl(w, defaultPath)
+			}
+		}
+		token.Logos = logos
+
+		// the validated logo.svg is published as is.
+		token.LogoSvgUrl = ""
+		if _, ok := tm.svgLogos[tokenUid]; ok {
+			svg, err := os.ReadFile(fmt.Sprintf("tokens/%s/logo.svg", tokenUid))
+			if err != nil {
+				return err
+			}
+			svgPath := fmt.Sprintf("tokens/%s.svg", tokenUid)
+			err = w.writeFile(svgPath, svg)
+			if err != nil {
+				return err
+			}
+			token.LogoSvgUrl = tm.cdnUrl(w, svgPath)
+		}
+
+		// the addresses share the logo of the token.
+		for i := range token.Addresses {
+			token.Addresses[i].LogoPngUrl = token.LogoPngUrl
+			token.Addresses[i].LogoSvgUrl = token.LogoSvgUrl
+		}
+	}
+	return nil
+}
+
+// cdnUrl returns the URL of the artifact of the writer on the CDN the dist directory is deployed to.
+func (tm *tokenManager) cdnUrl(w *distWriter, rel string) string {
+	return strings.TrimSuffix(tm.cdnBaseUrl, "/") + "/" + path.Join(w.profile.dir(), rel)
+}
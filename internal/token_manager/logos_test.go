@@ -0,0 +1,129 @@
+package tokenmanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// writeLogo writes a square RGBA logo of the size into tokens/:tokenUid/logo.png of the working directory.
+func writeLogo(t *testing.T, tokenUid string, size int) string {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 255 / size), G: uint8(y * 255 / size), B: 0x80, A: 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	logoPath := filepath.Join("tokens", tokenUid, "logo.png")
+	writeTree(t, ".", map[string]string{filepath.ToSlash(logoPath): buf.String()})
+	return logoPath
+}
+
+func TestLogoVariantSizes(t *testing.T) {
+	tests := []struct {
+		size int
+		want []int
+	}{
+		{64, []int{32, 64}},
+		{100, []int{32, 64}},
+		{128, []int{32, 64, 128}},
+		{256, []int{32, 64, 128, 256}},
+		{1024, []int{32, 64, 128, 256}},
+	}
+	for _, tt := range tests {
+		if got := logoVariantSizes(tt.size); !slices.Equal(got, tt.want) {
+			t.Errorf("logoVariantSizes(%d) = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestValidateLogoSize(t *testing.T) {
+	t.Chdir(t.TempDir())
+	tm := &tokenManager{}
+	tests := []struct {
+		size int
+		want string
+	}{
+		{64, "logo is 64x64, smaller than 256x256, the 128, 256 px variants are not generated"},
+		{128, "logo is 128x128, smaller than 256x256, the 256 px variants are not generated"},
+		{256, ""},
+		{512, ""},
+	}
+	for _, tt := range tests {
+		err := tm.validateLogoSize(writeLogo(t, fmt.Sprintf("logo%d", tt.size), tt.size))
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("validateLogoSize(%d) = %v, want nil", tt.size, err)
+		case tt.want != "" && (err == nil || err.Error() != tt.want || !IsWarning(err)):
+			t.Errorf("validateLogoSize(%d) = %v, want the warning %q", tt.size, err, tt.want)
+		}
+	}
+}
+
+func TestWriteLogos(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeLogo(t, "small", 64)
+	writeLogo(t, "large", 256)
+
+	tm := &tokenManager{}
+	tm.setDefaults()
+	tokens := map[string]*models.Token{
+		"small": {Uuid: "small", LogoSvgUrl: "https://file.example/small.svg", Addresses: []models.TokenAddress{
+			{NetworkId: 1, LogoPngUrl: "https://file.example/small.png", LogoSvgUrl: "https://file.example/small.svg"},
+		}},
+		"large": {Uuid: "large", OrderIndex: 1},
+	}
+	w := newDistWriter(t.TempDir(), ProfileMainnet)
+	if err := tm.writeLogos(context.Background(), w, tokens); err != nil {
+		t.Fatalf("writeLogos() error = %v", err)
+	}
+
+	base := "https://ma3xco.github.io/token-listing/mainnet/tokens/"
+	for tokenUid, sizes := range map[string][]int{"small": {32, 64}, "large": {32, 64, 128, 256}} {
+		logos := tokens[tokenUid].Logos
+		if got := slices.Sorted(maps.Keys(logos.Png)); !slices.Equal(got, sizes) {
+			t.Errorf("%s png variants = %v, want %v", tokenUid, got, sizes)
+		}
+		if got := slices.Sorted(maps.Keys(logos.Webp)); !slices.Equal(got, sizes) {
+			t.Errorf("%s webp variants = %v, want %v", tokenUid, got, sizes)
+		}
+		for _, size := range sizes {
+			rel := fmt.Sprintf("tokens/%s/logo_%d.png", tokenUid, size)
+			file, err := os.Open(filepath.Join(w.root, rel))
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, err := png.DecodeConfig(file)
+			file.Close()
+			if err != nil || config.Width != size || config.Height != size {
+				t.Errorf("%s = %dx%d, %v, want %dx%d", rel, config.Width, config.Height, err, size, size)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(w.root, "tokens", tokenUid+".png")); err != nil {
+			t.Errorf("the default logo of %s is missing: %v", tokenUid, err)
+		}
+	}
+
+	small := tokens["small"]
+	if small.LogoPngUrl != base+"small.png" || small.LogoSvgUrl != "" {
+		t.Errorf("small logo urls = %q, %q, want the CDN png and no svg", small.LogoPngUrl, small.LogoSvgUrl)
+	}
+	if address := small.Addresses[0]; address.LogoPngUrl != small.LogoPngUrl || address.LogoSvgUrl != "" {
+		t.Errorf("small address logo urls = %q, %q, want the logo urls of the token", address.LogoPngUrl, address.LogoSvgUrl)
+	}
+}
@@ -0,0 +1,174 @@
+package tokenmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/ma3xco/token-listing/pkg/signing"
+)
+
+// manifestPath is the path of the build manifest relative to the dist directory.
+const manifestPath = "manifest.json"
+
+// contentTypes maps the artifact extensions to their content types.
+var contentTypes = map[string]string{
+	".json": "application/json",
+	".png":  "image/png",
+	".svg":  "image/svg+xml",
+	".webp": "image/webp",
+}
+
+// contentType returns the content type of the artifact by its extension.
+func contentType(rel string) string {
+	if ct, ok := contentTypes[path.Ext(rel)]; ok {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// writeManifest writes the manifest.json listing every file written so far.
+func (tm *tokenManager) writeManifest(ctx context.Context, w *distWriter) (*models.Manifest, error) {
+	version, err := tm.resolveRegistryVersion(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	buildTime := tm.buildTime
+	if buildTime.IsZero() {
+		buildTime = time.Now()
+	}
+	manifest := &models.Manifest{
+		RegistryVersion: version,
+		BuildTime:       buildTime.UTC().Format(time.RFC3339),
+		SourceCommit:    tm.resolveSourceCommit(ctx),
+		Artifacts:       make([]models.ManifestArtifact, 0, len(w.files)),
+	}
+	for _, rel := range w.paths() {
+		file := w.files[rel]
+		manifest.Artifacts = append(manifest.Artifacts, models.ManifestArtifact{
+			Path:        rel,
+			Sha256:      file.sha256,
+			Size:        file.size,
+			ContentType: contentType(rel),
+		})
+	}
+	bytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = w.writeFile(manifestPath, bytes)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// verifyManifest reads the manifest back from the staging directory and checks
+// that it lists exactly the written files with their hashes.
+func (tm *tokenManager) verifyManifest(ctx context.Context, w *distWriter) error {
+	bytes, err := os.ReadFile(filepath.Join(w.root, manifestPath))
+	if err != nil {
+		return err
+	}
+	var manifest models.Manifest
+	err = json.Unmarshal(bytes, &manifest)
+	if err != nil {
+		return fmt.Errorf("cannot parse the manifest: %v", err)
+	}
+	listed := make(map[string]struct{}, len(manifest.Artifacts))
+	for _, artifact := range manifest.Artifacts {
+		file, ok := w.files[artifact.Path]
+		if !ok {
+			return fmt.Errorf("manifest lists unknown artifact %s", artifact.Path)
+		}
+		if artifact.Sha256 != file.sha256 || artifact.Size != file.size {
+			return fmt.Errorf("manifest entry of %s does not match the artifact", artifact.Path)
+		}
+		listed[artifact.Path] = struct{}{}
+	}
+	for _, rel := range w.paths() {
+		if _, ok := listed[rel]; !ok && !tm.isUnlistedArtifact(rel) {
+			return fmt.Errorf("artifact %s is missing from the manifest", rel)
+		}
+	}
+	return nil
+}
+
+// isUnlistedArtifact reports whether the artifact is not listed in the manifest,
+// the manifest can not list itself and the signatures are written after the manifest.
+func (tm *tokenManager) isUnlistedArtifact(rel string) bool {
+	return rel == manifestPath || strings.HasSuffix(rel, signing.SignatureExt)
+}
+
+// resolveRegistryVersion returns the registry version of the build.
+// unless set by the option, it is the number of the commits in the source repository,
+// which only increases on the main branch.
+// it fails if the version is lower than the version of the published build set by the option,
+// or of the build of the writer's profile in the dist directory.
+func (tm *tokenManager) resolveRegistryVersion(ctx context.Context, w *distWriter) (int64, error) {
+	version := tm.registryVersion
+	if version == 0 {
+		out, err := gitOutput(ctx, "rev-list", "--count", "HEAD")
+		if err != nil {
+			return 0, fmt.Errorf("cannot resolve the registry version: %v", err)
+		}
+		version, err = strconv.ParseInt(out, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot resolve the registry version: %v", err)
+		}
+	}
+	if version < tm.publishedRegistryVersion {
+		return 0, fmt.Errorf("registry version %d is lower than the published version %d", version, tm.publishedRegistryVersion)
+	}
+	bytes, err := os.ReadFile(filepath.Join(tm.distDir, w.profile.dir(), manifestPath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		return 0, err
+	}
+	var previous models.Manifest
+	if err := json.Unmarshal(bytes, &previous); err != nil {
+		tm.logger.Warnf("cannot parse the previous manifest: %v", err)
+		return version, nil
+	}
+	if version < previous.RegistryVersion {
+		return 0, fmt.Errorf("registry version %d is lower than the published version %d", version, previous.RegistryVersion)
+	}
+	return version, nil
+}
+
+// resolveSourceCommit returns the git commit of the source.
+// unless set by the option, it is read from git or the GITHUB_SHA environment variable.
+func (tm *tokenManager) resolveSourceCommit(ctx context.Context) string {
+	if tm.sourceCommit != "" {
+		return tm.sourceCommit
+	}
+	out, err := gitOutput(ctx, "rev-parse", "HEAD")
+	if err == nil {
+		return out
+	}
+	if sha := os.Getenv("GITHUB_SHA"); sha != "" {
+		return sha
+	}
+	tm.logger.Warnf("cannot resolve the source commit: %v", err)
+	return ""
+}
+
+// gitOutput runs the git command and returns its trimmed output.
+func gitOutput(ctx context.Context, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,84 @@
+package tokenmanager
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestResolveRegistryVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   int64
+		published string
+		dist      string
+		want      string
+	}{
+		{"fresh checkout", 10, "", "", ""},
+		{"published", 10, `{"registry_version": 10}`, "", ""},
+		{"behind the published manifest", 9, `{"registry_version": 10}`, "", "registry version 9 is lower than the published version 10"},
+		{"behind the dist manifest", 9, "", `{"registry_version": 10}`, "registry version 9 is lower than the published version 10"},
+		{"behind either manifest", 9, `{"registry_version": 8}`, `{"registry_version": 10}`, "lower than the published version 10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tm := &tokenManager{}
+			tm.setDefaults()
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+			tm.logger = logger
+			tm.distDir = filepath.Join(dir, "dist")
+			if err := WithRegistryVersion(tt.version)(tm); err != nil {
+				t.Fatal(err)
+			}
+			if tt.published != "" {
+				writeTree(t, dir, map[string]string{"published.json": tt.published})
+				if err := WithPreviousManifestFile(filepath.Join(dir, "published.json"))(tm); err != nil {
+					t.Fatalf("WithPreviousManifestFile() error = %v", err)
+				}
+			}
+			if tt.dist != "" {
+				writeTree(t, tm.distDir, map[string]string{manifestPath: tt.dist})
+			}
+
+			version, err := tm.resolveRegistryVersion(context.Background(), newDistWriter(t.TempDir(), ProfileAll))
+			if tt.want == "" {
+				if err != nil || version != tt.version {
+					t.Errorf("resolveRegistryVersion() = %d, %v, want %d", version, err, tt.version)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("resolveRegistryVersion() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPreviousManifestFile(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"invalid.json":     `{"registry_version": "10"}`,
+		"unversioned.json": `{"artifacts": []}`,
+	})
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"missing.json", "no such file"},
+		{"invalid.json", "cannot parse the previous manifest"},
+		{"unversioned.json", "has no registry version"},
+	}
+	for _, tt := range tests {
+		tm := &tokenManager{}
+		err := WithPreviousManifestFile(filepath.Join(dir, tt.file))(tm)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("WithPreviousManifestFile(%s) error = %v, want %q", tt.file, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package tokenmanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/imaging"
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// ValidateNetworks validates the networks in the memory.
+// Validation rules:
+// - id matches the folder name, the chain id is unique among the networks of the same type.
+// - network type and coin type are known, the coin type matches the network type
+// (60 or 714 for ethereum-like, 195 for TRON, 501 for Solana, 0 for UTXO).
+// - name, symbol and a CAIP-2 chain id are present, decimals are between 0 and 18.
+// - address regex compiles.
+// - explorer has a base URL and the templates of the network type, refer to models.Network.ValidateExplorer.
+// - an active network has exactly one native token, an inactive one at most one,
+// and the native token has the decimals of the network.
+// - icon png is not too large, square and between 64x64 and 1024x1024.
+// - icon svg, if provided, is a safe and self-contained svg document.
+// - icon URLs, if provided, are valid http(s) URLs.
+// Warnings (refer to IsWarning):
+// - icon png without alpha, with a wide transparent border, nearly blank,
+// near-uniform color or with a palette.
+func (tm *tokenManager) ValidateNetworks(ctx context.Context) map[int64][]error {
+	validationErrors := make(map[int64][]error)
+
+	// the chain ids by the network type, to find the networks defined twice.
+	chainIds := make(map[models.NetworkType]map[int64]int64)
+	// the native token addresses by the network id.
+	natives := make(map[int64][]models.TokenAddress)
+	for _, uid := range sortedTokenUids(tm.tokens) {
+		for _, address := range tm.tokens[uid].Addresses {
+			if address.IsNative {
+				natives[int64(address.NetworkId)] = append(natives[int64(address.NetworkId)], address)
+			}
+		}
+	}
+
+	networkIds := make([]int64, 0, len(tm.networks))
+	for networkId := range tm.networks {
+		networkIds = append(networkIds, networkId)
+	}
+	slices.Sort(networkIds)
+
+	for _, networkId := range networkIds {
+		network := tm.networks[networkId]
+		var errors []error
+
+		// Validate the definition
+		if network.Id != networkId {
+			errors = append(errors, fmt.Errorf("id %d does not match the folder name %d", network.Id, networkId))
+		}
+		if !network.NetworkType.IsKnown() {
+			errors = append(errors, fmt.Errorf("unknown network type %d", network.NetworkType))
+		} else if other, ok := chainIds[network.NetworkType][network.ChainId]; ok {
+			errors = append(errors, fmt.Errorf("chain id %d is already defined by network %d", network.ChainId, other))
+		} else {
+			if chainIds[network.NetworkType] == nil {
+				chainIds[network.NetworkType] = make(map[int64]int64)
+			}
+			chainIds[network.NetworkType][network.ChainId] = networkId
+		}
+		if !network.CoinType.IsKnown() {
+			errors = append(errors, fmt.Errorf("unknown coin type %d", network.CoinType))
+		} else if network.NetworkType.IsKnown() && !slices.Contains(network.NetworkType.CoinTypes(), network.CoinType) {
+			errors = append(errors, fmt.Errorf("coin type %d does not match the network type %d, expected one of %v",
+				network.CoinType, network.NetworkType, network.NetworkType.CoinTypes()))
+		}
+		if strings.TrimSpace(network.Name) == "" {
+			errors = append(errors, fmt.Errorf("name is required"))
+		}
+		if strings.TrimSpace(network.Symbol) == "" {
+			errors = append(errors, fmt.Errorf("symbol is required"))
+		}
+		if network.Decimals < 0 || network.Decimals > 18 {
+			errors = append(errors, fmt.Errorf("decimals must be between 0 and 18, got: %d", network.Decimals))
+		}
+		if _, err := network.CAIP2(); err != nil {
+			errors = append(errors, err)
+		}
+		if strings.TrimSpace(network.AddressRegex) == "" {
+			errors = append(errors, fmt.Errorf("address regex is required"))
+		} else if _, err := regexp.Compile(network.AddressRegex); err != nil {
+			errors = append(errors, fmt.Errorf("invalid address regex: %v", err))
+		}
+		if err := network.ValidateExplorer(); err != nil {
+			errors = append(errors, err)
+		}
+
+		// Validate the native token
+		switch n := len(natives[networkId]); {
+		case n == 0 && network.IsActive:
+			errors = append(errors, fmt.Errorf("active network has no native token"))
+		case n > 1:
+			uids := make([]string, 0, n)
+			for _, address := range natives[networkId] {
+				uids = append(uids, address.TokenUid)
+			}
+			errors = append(errors, fmt.Errorf("network has %d native tokens: %s", n, strings.Join(uids, ", ")))
+		case n == 1:
+			native := natives[networkId][0]
+			if int64(native.Decimals) != network.Decimals {
+				errors = append(errors, fmt.Errorf("native token %s has %d decimals, the network has %d",
+					native.TokenUid, native.Decimals, network.Decimals))
+			}
+		}
+
+		// Validate icon file exists and is a square PNG, with the same rules as the token logos
+		iconPath := fmt.Sprintf("networks/%d/icon.png", networkId)
+		if err := tm.validateLogoFile(iconPath); err != nil {
+			errors = append(errors, fmt.Errorf("icon file validation failed: %v", err))
+		} else {
+			errors = append(errors, tm.analyzeLogo(iconPath)...)
+		}
+
+		// Validate icon.svg if provided (optional)
+		if _, ok := tm.svgNetworkIcons[networkId]; ok {
+			if err := tm.validateSvgFile(fmt.Sprintf("networks/%d/icon.svg", networkId)); err != nil {
+				errors = append(errors, fmt.Errorf("svg icon validation failed: %v", err))
+			}
+		}
+
+		// Validate icon URLs format if provided (optional, the build points them at the CDN copies)
+		if err := tm.validateURL(network.IconPngUrl, "icon PNG", false); err != nil {
+			errors = append(errors, err)
+		}
+		if err := tm.validateURL(network.IconSvgUrl, "icon SVG", false); err != nil {
+			errors = append(errors, err)
+		}
+
+		if len(errors) > 0 {
+			validationErrors[networkId] = errors
+		}
+	}
+
+	return validationErrors
+}
+
+// writeNetworks writes networks.json, networks/:networkId.json and the network icons,
+// the icon.png is published at the default logo size as networks/:networkId.png
+// and the icon.svg, if any, as is as networks/:networkId.svg.
+// icon_png_url and icon_svg_url of the published networks point at the CDN copies.
+// only the active networks of the profile are published.
+func (tm *tokenManager) writeNetworks(ctx context.Context, w *distWriter) error {
+	networks := []models.Network{}
+	for _, network := range tm.ListNetworks(ctx) {
+		if w.profile.includes(network) && network.IsActive {
+			networks = append(networks, network)
+		}
+	}
+	for i := range networks {
+		network := &networks[i]
+		file, err := os.Open(fmt.Sprintf("networks/%d/icon.png", network.Id))
+		if err != nil {
+			return err
+		}
+		src, err := png.Decode(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("cannot decode the icon of network %d: %v", network.Id, err)
+		}
+		var buf bytes.Buffer
+		err = png.Encode(&buf, imaging.Resize(src, defaultLogoSize, defaultLogoSize))
+		if err != nil {
+			return err
+		}
+		pngPath := fmt.Sprintf("networks/%d.png", network.Id)
+		err = w.writeFile(pngPath, buf.Bytes())
+		if err != nil {
+			return err
+		}
+		network.IconPngUrl = tm.cdnUrl(w, pngPath)
+
+		// the validated icon.svg is published as is.
+		if _, ok := tm.svgNetworkIcons[network.Id]; ok {
+			svg, err := os.ReadFile(fmt.Sprintf("networks/%d/icon.svg", network.Id))
+			if err != nil {
+				return err
+			}
+			svgPath := fmt.Sprintf("networks/%d.svg", network.Id)
+			err = w.writeFile(svgPath, svg)
+			if err != nil {
+				return err
+			}
+			network.IconSvgUrl = tm.cdnUrl(w, svgPath)
+		}
+
+		err = w.writeJSON(fmt.Sprintf("networks/%d.json", network.Id), network)
+		if err != nil {
+			return err
+		}
+	}
+	return w.writeJSON("networks.json", networks)
+}
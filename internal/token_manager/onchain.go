@@ -0,0 +1,41 @@
+package tokenmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/onchain"
+)
+
+// verifyOnchain verifies the ERC-20 addresses of the token on the networks with an RPC url
+// against their contracts, refer to onchain.Verifier.Verify.
+// the addresses without a symbol or a name are compared with the symbol and the name of the token.
+// the name mismatches, and the upgradeable addresses that are not EIP-1967 proxies
+// (e.g., the other proxy patterns), are reported as warnings.
+func (tm *tokenManager) verifyOnchain(ctx context.Context, tokenUid string) []error {
+	token := tm.tokens[tokenUid]
+	var errs []error
+	for i, address := range token.Addresses {
+		network, ok := tm.networks[int64(address.NetworkId)]
+		if !ok || address.IsNative || !strings.EqualFold(address.TokenType, "ERC20") || !tm.onchainVerifier.Supports(network) {
+			continue
+		}
+		if address.Symbol == "" {
+			address.Symbol = token.Symbol
+		}
+		if address.Name == "" {
+			address.Name = token.Name
+		}
+		for _, err := range tm.onchainVerifier.Verify(ctx, network, address) {
+			var mismatch *onchain.Mismatch
+			if errors.As(err, &mismatch) && (mismatch.Field == "name" || (mismatch.Field == "upgradeable" && address.Upgradeable)) {
+				errs = append(errs, warningf("address[%d]: on-chain: %v", i, err))
+			} else {
+				errs = append(errs, fmt.Errorf("address[%d]: on-chain: %v", i, err))
+			}
+		}
+	}
+	return errs
+}
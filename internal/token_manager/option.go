@@ -1,3 +1,220 @@
 package tokenmanager
 
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/ma3xco/token-listing/internal/onchain"
+	"github.com/ma3xco/token-listing/internal/pricefeed"
+	"github.com/ma3xco/token-listing/pkg/signing"
+)
+
 type Option func(*tokenManager) error
+
+// WithDistDir sets the directory the build assets are published into.
+// default is ./dist.
+func WithDistDir(dir string) Option {
+	return func(tm *tokenManager) error {
+		if dir == "" {
+			return errors.New("dist directory is required")
+		}
+		tm.distDir = dir
+		return nil
+	}
+}
+
+// WithCdnBaseUrl sets the base URL the dist directory is deployed to,
+// the published logo URLs point at it.
+// default is https://ma3xco.github.io/token-listing.
+func WithCdnBaseUrl(baseUrl string) Option {
+	return func(tm *tokenManager) error {
+		if err := tm.validateURL(baseUrl, "CDN base", true); err != nil {
+			return err
+		}
+		tm.cdnBaseUrl = baseUrl
+		return nil
+	}
+}
+
+// WithMaxLogoPadding sets the transparent border of a 64x64 logo above which
+// the logo gets a warning, it is scaled with the logo size.
+// default is 8 pixels.
+func WithMaxLogoPadding(pixels int) Option {
+	return func(tm *tokenManager) error {
+		if pixels < 0 {
+			return errors.New("max logo padding must not be negative")
+		}
+		tm.maxLogoPadding = pixels
+		return nil
+	}
+}
+
+// WithMaxLogoHashDistance sets the hash distance (out of 256 bits) up to which
+// two logos are considered the same.
+// default is 10.
+func WithMaxLogoHashDistance(distance int) Option {
+	return func(tm *tokenManager) error {
+		if distance < 0 {
+			return errors.New("max logo hash distance must not be negative")
+		}
+		tm.maxLogoHashDistance = distance
+		return nil
+	}
+}
+
+// WithRegistryVersion sets the registry version written into the manifest.
+// default is the number of the commits in the source repository.
+func WithRegistryVersion(version int64) Option {
+	return func(tm *tokenManager) error {
+		if version <= 0 {
+			return errors.New("registry version must be positive")
+		}
+		tm.registryVersion = version
+		return nil
+	}
+}
+
+// WithPreviousManifestFile loads the manifest of the published build (e.g., the deployed manifest.json),
+// the build fails if its registry version is lower than the published one.
+// the manifest of the dist directory is always checked, but it does not exist on a fresh checkout.
+func WithPreviousManifestFile(path string) Option {
+	return func(tm *tokenManager) error {
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var manifest models.Manifest
+		if err := json.Unmarshal(bytes, &manifest); err != nil {
+			return fmt.Errorf("cannot parse the previous manifest %s: %v", path, err)
+		}
+		if manifest.RegistryVersion <= 0 {
+			return fmt.Errorf("previous manifest %s has no registry version", path)
+		}
+		tm.publishedRegistryVersion = manifest.RegistryVersion
+		return nil
+	}
+}
+
+// WithSourceCommit sets the source commit written into the manifest.
+// default is the HEAD commit of the source repository.
+func WithSourceCommit(commit string) Option {
+	return func(tm *tokenManager) error {
+		tm.sourceCommit = commit
+		return nil
+	}
+}
+
+// WithBuildTime sets the build time written into the manifest.
+// default is the current time.
+func WithBuildTime(t time.Time) Option {
+	return func(tm *tokenManager) error {
+		tm.buildTime = t
+		return nil
+	}
+}
+
+// WithSigningKey signs the manifest of the build with the ed25519 key,
+// and publishes the public key and the detached signature.
+func WithSigningKey(key ed25519.PrivateKey) Option {
+	return func(tm *tokenManager) error {
+		if len(key) != ed25519.PrivateKeySize {
+			return fmt.Errorf("signing key must be %d bytes, got: %d", ed25519.PrivateKeySize, len(key))
+		}
+		tm.signingKey = key
+		return nil
+	}
+}
+
+// WithSigningKeyFile loads the PEM encoded PKCS#8 ed25519 signing key from the file.
+// refer to WithSigningKey.
+func WithSigningKeyFile(path string) Option {
+	return func(tm *tokenManager) error {
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		key, err := signing.ParsePrivateKey(bytes)
+		if err != nil {
+			return err
+		}
+		return WithSigningKey(key)(tm)
+	}
+}
+
+// WithArtifactSignatures signs every artifact of the build, not only the manifest.
+// it requires a signing key, the build fails without one.
+func WithArtifactSignatures() Option {
+	return func(tm *tokenManager) error {
+		tm.signArtifacts = true
+		return nil
+	}
+}
+
+// WithPreviousTokens sets the tokens of the previous registry state (e.g., the published tokens.json),
+// the build publishes changelog.json with the changes since then.
+// the label identifies the previous state in the changelog.
+func WithPreviousTokens(label string, tokens []models.Token) Option {
+	return func(tm *tokenManager) error {
+		if tokens == nil {
+			tokens = []models.Token{}
+		}
+		tm.previousLabel = label
+		tm.previousTokens = tokens
+		return nil
+	}
+}
+
+// WithProfiles sets the build profiles, the build publishes a tree for every profile:
+// the all profile at the root of the dist directory and the others into dist/:profile,
+// without the tokens whose addresses are all on the networks of the other profiles.
+// default is the all profile only.
+func WithProfiles(profiles ...Profile) Option {
+	return func(tm *tokenManager) error {
+		if len(profiles) == 0 {
+			return errors.New("at least one build profile is required")
+		}
+		seen := make(map[Profile]struct{}, len(profiles))
+		for _, profile := range profiles {
+			if !profile.isKnown() {
+				return fmt.Errorf("unknown build profile %q", profile)
+			}
+			if _, ok := seen[profile]; ok {
+				return fmt.Errorf("duplicate build profile %q", profile)
+			}
+			seen[profile] = struct{}{}
+		}
+		tm.profiles = profiles
+		return nil
+	}
+}
+
+// WithPriceChecker checks the live price urls of the tokens with the checker during the validation,
+// the responses are validated against the contract of models.Token.LivePriceUrl.
+// default is no check, the validation does not access the network.
+func WithPriceChecker(checker *pricefeed.Checker) Option {
+	return func(tm *tokenManager) error {
+		if checker == nil {
+			return errors.New("price checker is required")
+		}
+		tm.priceChecker = checker
+		return nil
+	}
+}
+
+// WithOnchainVerifier verifies the ERC-20 addresses against their contracts with the verifier during the validation,
+// on the ethereum-like networks the verifier has an RPC url for.
+// default is no verification, the validation does not access the network.
+func WithOnchainVerifier(verifier *onchain.Verifier) Option {
+	return func(tm *tokenManager) error {
+		if verifier == nil {
+			return errors.New("on-chain verifier is required")
+		}
+		tm.onchainVerifier = verifier
+		return nil
+	}
+}
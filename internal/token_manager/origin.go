@@ -0,0 +1,201 @@
+package tokenmanager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// assetGraphPath is the path of the asset graph relative to the dist directory.
+const assetGraphPath = "asset_graph.json"
+
+// maxOriginChain is the number of the sources followed before a chain is reported as a cycle.
+const maxOriginChain = 16
+
+// validateOrigins validates the origins of the token addresses:
+// - the origin fields are consistent, refer to models.Origin.Validate.
+// - the source is an address of the registry, other than the address itself.
+// - the source of a bridged address is on another network.
+// - the chain of the sources ends without a cycle.
+// Warnings (refer to IsWarning):
+// - the source belongs to a token that is neither the token itself, nor its wrapped token,
+// nor listed in its same_asset_as.
+func (tm *tokenManager) validateOrigins(tokenUid string) []error {
+	token := tm.tokens[tokenUid]
+	var errors []error
+	for i, address := range token.Addresses {
+		origin := address.Origin
+		if err := origin.Validate(); err != nil {
+			errors = append(errors, fmt.Errorf("address[%d]: origin: %v", i, err))
+			continue
+		}
+		if origin == nil || origin.Source == nil {
+			continue
+		}
+		sourceUid, source, ok := tm.resolveAddressRef(*origin.Source)
+		if !ok {
+			errors = append(errors, fmt.Errorf("address[%d]: origin: source %d/%s is not an address of the registry",
+				i, origin.Source.NetworkId, origin.Source.Address))
+			continue
+		}
+		if origin.Kind == models.OriginKind_BRIDGED && source.NetworkId == address.NetworkId {
+			errors = append(errors, fmt.Errorf("address[%d]: origin: bridged address must have its source on another network", i))
+			continue
+		}
+		if err := tm.validateOriginChain(address, *origin.Source); err != nil {
+			errors = append(errors, fmt.Errorf("address[%d]: origin: %v", i, err))
+			continue
+		}
+		if sourceUid != tokenUid && sourceUid != token.WrappedTokenUuid && !slices.Contains(token.SameAssetAs, sourceUid) {
+			errors = append(errors, warningf("address[%d]: origin: source %d/%s belongs to token %s, which is not the wrapped token or in same_asset_as",
+				i, origin.Source.NetworkId, origin.Source.Address, sourceUid))
+		}
+	}
+	return errors
+}
+
+// validateOriginChain follows the sources of the address until an address without a source.
+func (tm *tokenManager) validateOriginChain(address models.TokenAddress, source models.AddressRef) error {
+	visited := map[string]struct{}{fmt.Sprintf("%d/%s", address.NetworkId, address.Address): {}}
+	for range maxOriginChain {
+		_, next, ok := tm.resolveAddressRef(source)
+		if !ok {
+			return fmt.Errorf("source %d/%s is not an address of the registry", source.NetworkId, source.Address)
+		}
+		key := fmt.Sprintf("%d/%s", next.NetworkId, next.Address)
+		if _, ok := visited[key]; ok {
+			return fmt.Errorf("source %d/%s leads back to the address", source.NetworkId, source.Address)
+		}
+		visited[key] = struct{}{}
+		if next.Origin == nil || next.Origin.Source == nil {
+			return nil
+		}
+		source = *next.Origin.Source
+	}
+	return fmt.Errorf("source chain is longer than %d addresses", maxOriginChain)
+}
+
+// writeAssetGraph writes asset_graph.json with the published addresses that have an origin or are a source,
+// the edges to the sources that are not published are left out.
+func (tm *tokenManager) writeAssetGraph(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	// the published addresses by the network id and the canonical address.
+	published := make(map[int64]map[string]models.AssetNode)
+	for _, tokenUid := range sortedTokenUids(tokens) {
+		token := tokens[tokenUid]
+		for _, address := range token.Addresses {
+			network := tm.networks[int64(address.NetworkId)]
+			node := models.AssetNode{
+				Id:        address.Caip19,
+				NetworkId: address.NetworkId,
+				Address:   network.CanonicalAddress(address.Address),
+				TokenUuid: tokenUid,
+				Symbol:    token.Symbol,
+			}
+			if address.Origin != nil {
+				node.Origin = address.Origin.Kind
+			}
+			if published[network.Id] == nil {
+				published[network.Id] = make(map[string]models.AssetNode)
+			}
+			published[network.Id][node.Address] = node
+		}
+	}
+
+	nodes := make(map[string]models.AssetNode)
+	graph := models.AssetGraph{Nodes: []models.AssetNode{}, Edges: []models.AssetEdge{}, Assets: []models.AssetGroup{}}
+	// the source node id of every bridged and wrapped node id.
+	sources := make(map[string]string)
+	for _, tokenUid := range sortedTokenUids(tokens) {
+		for _, address := range tokens[tokenUid].Addresses {
+			if address.Origin == nil {
+				continue
+			}
+			network := tm.networks[int64(address.NetworkId)]
+			node := published[network.Id][network.CanonicalAddress(address.Address)]
+			nodes[node.Id] = node
+			if address.Origin.Source == nil {
+				continue
+			}
+			sourceNetwork, ok := tm.networks[int64(address.Origin.Source.NetworkId)]
+			if !ok {
+				return fmt.Errorf("token %s: network %d not found", tokenUid, address.Origin.Source.NetworkId)
+			}
+			source, ok := published[sourceNetwork.Id][sourceNetwork.CanonicalAddress(address.Origin.Source.Address)]
+			if !ok {
+				continue
+			}
+			nodes[source.Id] = source
+			sources[node.Id] = source.Id
+			graph.Edges = append(graph.Edges, models.AssetEdge{
+				From:   node.Id,
+				To:     source.Id,
+				Kind:   address.Origin.Kind,
+				Bridge: address.Origin.Bridge,
+			})
+		}
+	}
+
+	// the groups are the connected components of the edges and the tokens.
+	parent := make(map[string]string, len(nodes))
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] == id {
+			return id
+		}
+		parent[id] = find(parent[id])
+		return parent[id]
+	}
+	union := func(a, b string) {
+		a, b = find(a), find(b)
+		if a != b {
+			parent[max(a, b)] = min(a, b)
+		}
+	}
+	tokenNodes := make(map[string]string)
+	for id, node := range nodes {
+		parent[id] = id
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for id, node := range nodes {
+		if other, ok := tokenNodes[node.TokenUuid]; ok {
+			union(id, other)
+		} else {
+			tokenNodes[node.TokenUuid] = id
+		}
+	}
+	for from, to := range sources {
+		union(from, to)
+	}
+	groups := make(map[string]*models.AssetGroup)
+	for id := range nodes {
+		root := find(id)
+		if groups[root] == nil {
+			groups[root] = &models.AssetGroup{Roots: []string{}}
+		}
+		groups[root].Members = append(groups[root].Members, id)
+		if _, ok := sources[id]; !ok {
+			groups[root].Roots = append(groups[root].Roots, id)
+		}
+	}
+	for _, group := range groups {
+		sort.Strings(group.Members)
+		sort.Strings(group.Roots)
+		graph.Assets = append(graph.Assets, *group)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].Id < graph.Nodes[j].Id
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].To != graph.Edges[j].To {
+			return graph.Edges[i].To < graph.Edges[j].To
+		}
+		return graph.Edges[i].From < graph.Edges[j].From
+	})
+	sort.Slice(graph.Assets, func(i, j int) bool {
+		return graph.Assets[i].Members[0] < graph.Assets[j].Members[0]
+	})
+	return w.writeJSON(assetGraphPath, graph)
+}
@@ -0,0 +1,24 @@
+package tokenmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ma3xco/token-listing/internal/pricefeed"
+)
+
+// checkLivePrice fetches the live price url with the price checker and validates the response.
+// the stale prices and the prices without an update time are reported as warnings.
+func (tm *tokenManager) checkLivePrice(ctx context.Context, url string) []error {
+	_, problems := tm.priceChecker.Check(ctx, url)
+	errs := make([]error, 0, len(problems))
+	for _, problem := range problems {
+		if errors.Is(problem, pricefeed.ErrStale) || errors.Is(problem, pricefeed.ErrUnknownAge) {
+			errs = append(errs, warningf("live price: %v", problem))
+		} else {
+			errs = append(errs, fmt.Errorf("live price: %v", problem))
+		}
+	}
+	return errs
+}
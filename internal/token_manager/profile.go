@@ -0,0 +1,90 @@
+package tokenmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// Profile is a build profile, it selects the networks the published registry is for.
+type Profile string
+
+const (
+	// ProfileAll publishes the tokens of every network at the root of the dist directory.
+	ProfileAll Profile = "all"
+
+	// ProfileMainnet publishes the tokens of the mainnet networks into dist/mainnet.
+	ProfileMainnet Profile = "mainnet"
+
+	// ProfileTestnet publishes the tokens of the testnet networks into dist/testnet.
+	ProfileTestnet Profile = "testnet"
+)
+
+// Profiles is the list of the build profiles.
+var Profiles = []Profile{ProfileAll, ProfileMainnet, ProfileTestnet}
+
+// ParseProfiles parses a comma-separated list of build profiles (e.g., "all,mainnet").
+// it returns an error if any.
+func ParseProfiles(s string) ([]Profile, error) {
+	var profiles []Profile
+	for _, name := range strings.Split(s, ",") {
+		profile := Profile(strings.TrimSpace(name))
+		if !profile.isKnown() {
+			return nil, fmt.Errorf("unknown build profile %q, expected one of %v", profile, Profiles)
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func (p Profile) isKnown() bool {
+	for _, profile := range Profiles {
+		if p == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// dir returns the directory of the profile relative to the dist directory,
+// the all profile is published at the root.
+func (p Profile) dir() string {
+	if p == ProfileAll {
+		return ""
+	}
+	return string(p)
+}
+
+// includes reports whether the network is published by the profile.
+func (p Profile) includes(network models.Network) bool {
+	switch p {
+	case ProfileMainnet:
+		return !network.IsTestnet
+	case ProfileTestnet:
+		return network.IsTestnet
+	default:
+		return true
+	}
+}
+
+// profileToken returns the token with only the addresses on the networks of the profile,
+// it returns false if every address of the token is on the networks of the other profiles.
+// the tokens without addresses are published by every profile.
+func (tm *tokenManager) profileToken(profile Profile, token models.Token) (models.Token, bool) {
+	if profile == ProfileAll || len(token.Addresses) == 0 {
+		return token, true
+	}
+	addresses := make([]models.TokenAddress, 0, len(token.Addresses))
+	for _, address := range token.Addresses {
+		network, ok := tm.networks[int64(address.NetworkId)]
+		if !ok || profile.includes(network) {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return models.Token{}, false
+	}
+	token.Addresses = addresses
+	return token, true
+}
@@ -0,0 +1,125 @@
+package tokenmanager
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// TokenFilter filters the tokens listed by ListTokens.
+// the zero value matches every token.
+type TokenFilter struct {
+	// The network id the token must have an address on, 0 matches any network.
+	NetworkId int64
+
+	// The tag the token must have, empty matches any tag.
+	Tag string
+
+	// Whether the token must be featured or not, nil matches both.
+	Featured *bool
+
+	// Whether the token must be a stable token or not, nil matches both.
+	Stable *bool
+
+	// The prefix of the symbol or the name (case-insensitive), empty matches any token.
+	Query string
+}
+
+// GetToken returns a copy of the listed token with the uid, refer to listedToken.
+func (tm *tokenManager) GetToken(ctx context.Context, uid string) (*models.Token, bool) {
+	token, ok := tm.tokens[uid]
+	if !ok {
+		return nil, false
+	}
+	listed, ok := tm.listedToken(*token)
+	if !ok {
+		return nil, false
+	}
+	return copyToken(&listed), true
+}
+
+// GetTokenByAddress returns a copy of the listed token with the listed address on the network,
+// the address is canonicalized before the lookup, refer to models.Network.CanonicalAddress.
+func (tm *tokenManager) GetTokenByAddress(ctx context.Context, networkId int64, address string) (*models.Token, bool) {
+	network, ok := tm.networks[networkId]
+	if !ok {
+		return nil, false
+	}
+	uid, ok := tm.canonicalTokenAddresses[networkId][network.CanonicalAddress(address)]
+	if !ok {
+		return nil, false
+	}
+	token, ok := tm.GetToken(ctx, uid)
+	if !ok {
+		return nil, false
+	}
+	// the delisted addresses and the addresses on the inactive networks are not listed.
+	listed := slices.ContainsFunc(token.Addresses, func(tokenAddress models.TokenAddress) bool {
+		return int64(tokenAddress.NetworkId) == networkId && network.CanonicalAddress(tokenAddress.Address) == network.CanonicalAddress(address)
+	})
+	if !listed {
+		return nil, false
+	}
+	return token, true
+}
+
+// ListTokens returns the copies of the listed tokens matching the filter,
+// sorted by the order index and then by the uid.
+func (tm *tokenManager) ListTokens(ctx context.Context, filter TokenFilter) []models.Token {
+	query := strings.ToLower(strings.TrimSpace(filter.Query))
+	tokens := []models.Token{}
+	published := tm.publishedTokens(ProfileAll)
+	for _, uid := range sortedTokenUids(published) {
+		token := published[uid]
+		if filter.NetworkId != 0 && !slices.ContainsFunc(token.Addresses, func(address models.TokenAddress) bool {
+			return int64(address.NetworkId) == filter.NetworkId
+		}) {
+			continue
+		}
+		if filter.Tag != "" && !slices.Contains(token.Tags, filter.Tag) {
+			continue
+		}
+		if filter.Featured != nil && token.IsFeatured != *filter.Featured {
+			continue
+		}
+		if filter.Stable != nil && token.IsStableToken != *filter.Stable {
+			continue
+		}
+		if query != "" &&
+			!strings.HasPrefix(strings.ToLower(token.Symbol), query) &&
+			!strings.HasPrefix(strings.ToLower(token.Name), query) {
+			continue
+		}
+		tokens = append(tokens, *copyToken(token))
+	}
+	return tokens
+}
+
+// ListNetworks returns the networks sorted by the id.
+func (tm *tokenManager) ListNetworks(ctx context.Context) []models.Network {
+	networks := make([]models.Network, 0, len(tm.networks))
+	for _, network := range tm.networks {
+		networks = append(networks, network)
+	}
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Id < networks[j].Id
+	})
+	return networks
+}
+
+// copyToken returns a copy of the token, the callers are free to modify it.
+func copyToken(token *models.Token) *models.Token {
+	c := *token
+	c.Addresses = slices.Clone(token.Addresses)
+	for i := range c.Addresses {
+		c.Addresses[i].Lifecycle = c.Addresses[i].Lifecycle.Clone()
+		c.Addresses[i].Origin = c.Addresses[i].Origin.Clone()
+	}
+	c.Lifecycle = token.Lifecycle.Clone()
+	c.Tags = slices.Clone(token.Tags)
+	c.SameAssetAs = slices.Clone(token.SameAssetAs)
+	return &c
+}
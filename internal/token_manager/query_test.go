@@ -0,0 +1,135 @@
+package tokenmanager
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// newQueryTestManager returns a token manager with a listed token on an active and an inactive network,
+// and a disabled, a scam and a delisted token.
+func newQueryTestManager() *tokenManager {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	tm := &tokenManager{
+		logger: logger,
+		networks: map[int64]models.Network{
+			2: {Id: 2, NetworkType: models.NetworkType_NETWORK_TYPE_ETH_LIKE, ChainId: 1, CoinType: models.Coin_TYPE_ETH, IsActive: true},
+			3: {Id: 3, NetworkType: models.NetworkType_NETWORK_TYPE_ETH_LIKE, ChainId: 56, CoinType: models.Coin_TYPE_ETH},
+		},
+		tokens: map[string]*models.Token{
+			"listed": {Uuid: "listed", Symbol: "LST", Name: "Listed", OrderIndex: 1, Addresses: []models.TokenAddress{
+				{NetworkId: 2, Address: "0x00000000000000000000000000000000000000aa"},
+				{NetworkId: 3, Address: "0x00000000000000000000000000000000000000ab"},
+			}},
+			"disabled": {Uuid: "disabled", Symbol: "DIS", Name: "Disabled", OrderIndex: 2, IsDisabled: true, Addresses: []models.TokenAddress{
+				{NetworkId: 2, Address: "0x00000000000000000000000000000000000000bb"},
+			}},
+			"scam": {Uuid: "scam", Symbol: "LSTX", Name: "Listed scam", OrderIndex: 3, IsScam: true, Addresses: []models.TokenAddress{
+				{NetworkId: 2, Address: "0x00000000000000000000000000000000000000cc"},
+			}},
+			"delisted": {Uuid: "delisted", Symbol: "DEL", Name: "Delisted", OrderIndex: 4,
+				Lifecycle: &models.Lifecycle{Status: models.LifecycleStatus_DELISTED, Since: "2026-01-01", Reason: "rug pull"},
+				Addresses: []models.TokenAddress{
+					{NetworkId: 2, Address: "0x00000000000000000000000000000000000000dd"},
+				}},
+		},
+		canonicalTokenAddresses: map[int64]map[string]string{
+			2: {
+				"0x00000000000000000000000000000000000000aa": "listed",
+				"0x00000000000000000000000000000000000000bb": "disabled",
+				"0x00000000000000000000000000000000000000cc": "scam",
+				"0x00000000000000000000000000000000000000dd": "delisted",
+			},
+			3: {
+				"0x00000000000000000000000000000000000000ab": "listed",
+			},
+		},
+	}
+	return tm
+}
+
+func TestGetTokenListed(t *testing.T) {
+	tm := newQueryTestManager()
+	ctx := context.Background()
+	for _, uid := range []string{"disabled", "scam", "delisted", "missing"} {
+		if token, ok := tm.GetToken(ctx, uid); ok {
+			t.Errorf("GetToken(%s) = %v, want not found", uid, token.Uuid)
+		}
+	}
+	token, ok := tm.GetToken(ctx, "listed")
+	if !ok || len(token.Addresses) != 1 || token.Addresses[0].NetworkId != 2 {
+		t.Errorf("GetToken(listed) = %v, %v, want the address on the active network only", token, ok)
+	}
+	if len(tm.tokens["listed"].Addresses) != 2 {
+		t.Errorf("GetToken() modified the token in the memory")
+	}
+}
+
+func TestGetTokenByAddressListed(t *testing.T) {
+	tm := newQueryTestManager()
+	tests := []struct {
+		networkId int64
+		address   string
+		want      string
+	}{
+		{2, "0x00000000000000000000000000000000000000AA", "listed"},
+		// the address of a listed token on an inactive network.
+		{3, "0x00000000000000000000000000000000000000ab", ""},
+		{2, "0x00000000000000000000000000000000000000bb", ""},
+		{2, "0x00000000000000000000000000000000000000cc", ""},
+		{2, "0x00000000000000000000000000000000000000dd", ""},
+		{4, "0x00000000000000000000000000000000000000aa", ""},
+	}
+	for _, tt := range tests {
+		token, ok := tm.GetTokenByAddress(context.Background(), tt.networkId, tt.address)
+		switch {
+		case tt.want == "" && ok:
+			t.Errorf("GetTokenByAddress(%d, %s) = %s, want not found", tt.networkId, tt.address, token.Uuid)
+		case tt.want != "" && (!ok || token.Uuid != tt.want):
+			t.Errorf("GetTokenByAddress(%d, %s) = %v, %v, want %s", tt.networkId, tt.address, token, ok, tt.want)
+		}
+	}
+}
+
+func TestListTokensListed(t *testing.T) {
+	tm := newQueryTestManager()
+	tests := []struct {
+		name   string
+		filter TokenFilter
+		want   []string
+	}{
+		{"all", TokenFilter{}, []string{"listed"}},
+		{"query", TokenFilter{Query: "lst"}, []string{"listed"}},
+		{"disabled", TokenFilter{NetworkId: 2, Query: "dis"}, nil},
+		{"inactive network", TokenFilter{NetworkId: 3}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uids []string
+			for _, token := range tm.ListTokens(context.Background(), tt.filter) {
+				uids = append(uids, token.Uuid)
+			}
+			if !slices.Equal(uids, tt.want) {
+				t.Errorf("ListTokens(%+v) = %v, want %v", tt.filter, uids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchIndexListed(t *testing.T) {
+	tm := newQueryTestManager()
+	idx := tm.SearchIndex(context.Background())
+	if len(idx.Tokens) != 1 || idx.Tokens[0].Uid != "listed" || len(idx.Tokens[0].Addresses) != 1 {
+		t.Fatalf("SearchIndex() tokens = %+v, want the listed token with its active address", idx.Tokens)
+	}
+	for _, query := range []string{"dis", "0x00000000000000000000000000000000000000cc", "0x00000000000000000000000000000000000000ab"} {
+		if results := idx.Search(query, 0); len(results) != 0 {
+			t.Errorf("Search(%q) = %+v, want no results", query, results)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package tokenmanager
+
+import (
+	"context"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/ma3xco/token-listing/pkg/search"
+)
+
+// searchIndexPath is the path of the search index relative to the dist directory.
+const searchIndexPath = "search_index.json"
+
+// SearchIndex returns the search index of the listed tokens in the memory.
+func (tm *tokenManager) SearchIndex(ctx context.Context) *search.Index {
+	return tm.searchIndex(tm.publishedTokens(ProfileAll))
+}
+
+// writeSearchIndex writes the search index of the published tokens using the dist writer.
+func (tm *tokenManager) writeSearchIndex(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	return w.writeJSON(searchIndexPath, tm.searchIndex(tokens))
+}
+
+// searchIndex builds the search index of the tokens, the addresses are indexed in their canonical form.
+func (tm *tokenManager) searchIndex(tokens map[string]*models.Token) *search.Index {
+	entries := make([]search.Entry, 0, len(tokens))
+	for _, tokenUid := range sortedTokenUids(tokens) {
+		token := tokens[tokenUid]
+		_, featured := tm.featuredTokens[tokenUid]
+		entry := search.Entry{
+			Uid:        tokenUid,
+			Symbol:     token.Symbol,
+			Name:       token.Name,
+			OrderIndex: token.OrderIndex,
+			Featured:   featured,
+		}
+		for _, address := range token.Addresses {
+			entry.Addresses = append(entry.Addresses, search.Address{
+				NetworkId: int64(address.NetworkId),
+				Address:   tm.networks[int64(address.NetworkId)].CanonicalAddress(address.Address),
+			})
+		}
+		entries = append(entries, entry)
+	}
+	return search.Build(entries)
+}
@@ -0,0 +1,80 @@
+package tokenmanager
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ma3xco/token-listing/pkg/signing"
+)
+
+// writePublicKey publishes the public key of the signing key, if any.
+func (tm *tokenManager) writePublicKey(ctx context.Context, w *distWriter) error {
+	if tm.signingKey == nil {
+		return nil
+	}
+	bytes, err := signing.MarshalPublicKey(tm.signingKey.Public().(ed25519.PublicKey))
+	if err != nil {
+		return err
+	}
+	return w.writeFile(signing.PublicKeyPath, bytes)
+}
+
+// writeSignatures writes the detached signature of the manifest and,
+// if the artifact signatures are enabled, of every other artifact.
+// it must be called after the manifest is written.
+func (tm *tokenManager) writeSignatures(ctx context.Context, w *distWriter) error {
+	if tm.signingKey == nil {
+		return nil
+	}
+	paths := []string{manifestPath}
+	if tm.signArtifacts {
+		paths = w.paths()
+	}
+	for _, rel := range paths {
+		if strings.HasSuffix(rel, signing.SignatureExt) {
+			continue
+		}
+		bytes, err := os.ReadFile(filepath.Join(w.root, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		err = w.writeFile(rel+signing.SignatureExt, signing.Sign(tm.signingKey, bytes))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifySignatures verifies every written signature against the public key.
+func (tm *tokenManager) verifySignatures(ctx context.Context, w *distWriter) error {
+	if tm.signingKey == nil {
+		return nil
+	}
+	publicKey := tm.signingKey.Public().(ed25519.PublicKey)
+	for _, rel := range w.paths() {
+		if !strings.HasSuffix(rel, signing.SignatureExt) {
+			continue
+		}
+		signature, err := os.ReadFile(filepath.Join(w.root, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		signed := strings.TrimSuffix(rel, signing.SignatureExt)
+		bytes, err := os.ReadFile(filepath.Join(w.root, filepath.FromSlash(signed)))
+		if err != nil {
+			return err
+		}
+		if err := signing.Verify(publicKey, bytes, signature); err != nil {
+			return fmt.Errorf("signature of %s: %w", signed, err)
+		}
+	}
+	if _, ok := w.files[manifestPath+signing.SignatureExt]; !ok {
+		return fmt.Errorf("manifest signature is missing")
+	}
+	return nil
+}
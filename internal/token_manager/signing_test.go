@@ -0,0 +1,24 @@
+package tokenmanager
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildTokensArtifactSignaturesWithoutKey(t *testing.T) {
+	parent := t.TempDir()
+	tm := &tokenManager{}
+	tm.setDefaults()
+	tm.distDir = filepath.Join(parent, "dist")
+	if err := WithArtifactSignatures()(tm); err != nil {
+		t.Fatal(err)
+	}
+	err := tm.BuildTokens(context.Background())
+	if err == nil || err.Error() != "artifact signatures require a signing key" {
+		t.Fatalf("BuildTokens() error = %v, want the missing signing key", err)
+	}
+	if got := entries(t, parent); len(got) != 0 {
+		t.Errorf("BuildTokens() without a signing key wrote %v", got)
+	}
+}
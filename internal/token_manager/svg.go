@@ -0,0 +1,225 @@
+package tokenmanager
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxSvgFileSize is the size limit of the logo.svg.
+	maxSvgFileSize = 100 * 1024
+
+	// maxSvgViewBoxSize is the limit of the viewBox width and height.
+	maxSvgViewBoxSize = 10000
+)
+
+// svgShapeElements are the elements that draw something,
+// a logo without any of them rasterizes into a blank image.
+var svgShapeElements = map[string]struct{}{
+	"path": {}, "rect": {}, "circle": {}, "ellipse": {}, "line": {},
+	"polyline": {}, "polygon": {}, "text": {}, "use": {},
+}
+
+// svgForbiddenElements are the elements that run code, embed other documents or raster data,
+// and the animation elements, which can set the attributes (e.g., href) the validation checks.
+var svgForbiddenElements = map[string]struct{}{
+	"script": {}, "foreignobject": {}, "image": {}, "iframe": {}, "embed": {}, "object": {},
+	"audio": {}, "video": {}, "handler": {}, "listener": {},
+	"animate": {}, "set": {}, "animatemotion": {}, "animatetransform": {}, "animatecolor": {}, "discard": {},
+}
+
+// svgAnimationValueAttrs are the attributes that hold the values an animation sets.
+var svgAnimationValueAttrs = map[string]struct{}{
+	"values": {}, "to": {}, "from": {}, "by": {},
+}
+
+// validateSvgFile validates that the logo.svg is a safe, self-contained SVG document:
+// - not larger than 100KB and parseable XML without DTD or entities.
+// - the root element is <svg> with a sane viewBox.
+// - no <script>, <foreignObject>, <image> or other embedding elements, no animation elements.
+// - no event handler attributes (on*), no javascript: URIs.
+// - no external references, href and url() may only point at the fragments of the document,
+// and no attribute is animated into an href.
+// - no embedded data (data: URIs), no CSS @import, no CSS escapes.
+// - at least one shape element, so the logo does not rasterize into a blank image.
+func (tm *tokenManager) validateSvgFile(svgPath string) error {
+	fileInfo, err := os.Stat(svgPath)
+	if err != nil {
+		return fmt.Errorf("svg file does not exist: %v", err)
+	}
+	if fileInfo.Size() > maxSvgFileSize {
+		return fmt.Errorf("svg file is too large: %d bytes (max: %d bytes)", fileInfo.Size(), maxSvgFileSize)
+	}
+	content, err := os.ReadFile(svgPath)
+	if err != nil {
+		return fmt.Errorf("cannot read svg file: %v", err)
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.Strict = true
+	depth := 0
+	shapes := 0
+	rootSeen := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("svg is not a valid XML: %v", err)
+		}
+		switch t := token.(type) {
+		case xml.Directive:
+			return errors.New("svg must not contain DTD or entity declarations")
+		case xml.ProcInst:
+			if t.Target != "xml" {
+				return fmt.Errorf("svg must not contain processing instruction <?%s?>", t.Target)
+			}
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if depth == 0 {
+				if rootSeen {
+					return errors.New("svg must have a single root element")
+				}
+				rootSeen = true
+				if name != "svg" {
+					return fmt.Errorf("svg root element must be <svg>, got: <%s>", t.Name.Local)
+				}
+				if err := validateSvgViewBox(t); err != nil {
+					return err
+				}
+			}
+			if _, ok := svgForbiddenElements[name]; ok {
+				return fmt.Errorf("svg must not contain <%s> elements", t.Name.Local)
+			}
+			if _, ok := svgShapeElements[name]; ok {
+				shapes++
+			}
+			for _, attr := range t.Attr {
+				if err := validateSvgAttr(t.Name.Local, attr); err != nil {
+					return err
+				}
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			// the text of <style> elements and the CDATA sections.
+			if err := validateSvgStyle(string(t)); err != nil {
+				return err
+			}
+		}
+	}
+	if !rootSeen {
+		return errors.New("svg has no root element")
+	}
+	if shapes == 0 {
+		return errors.New("svg has no shape elements, it renders a blank image")
+	}
+	return nil
+}
+
+// validateSvgViewBox validates that the root element has a viewBox of 4 finite numbers
+// with a positive width and height, not larger than 10000 and not wider or taller than 2:1.
+func validateSvgViewBox(root xml.StartElement) error {
+	for _, attr := range root.Attr {
+		if attr.Name.Local != "viewBox" {
+			continue
+		}
+		fields := strings.FieldsFunc(attr.Value, func(r rune) bool { return r == ' ' || r == ',' || r == '\t' || r == '\n' })
+		if len(fields) != 4 {
+			return fmt.Errorf("svg viewBox must have 4 numbers, got: %q", attr.Value)
+		}
+		var values [4]float64
+		for i, field := range fields {
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("svg viewBox must have 4 numbers, got: %q", attr.Value)
+			}
+			values[i] = v
+		}
+		width, height := values[2], values[3]
+		if width <= 0 || height <= 0 || width > maxSvgViewBoxSize || height > maxSvgViewBoxSize {
+			return fmt.Errorf("svg viewBox size must be between 0 and %d, got: %gx%g", maxSvgViewBoxSize, width, height)
+		}
+		if width/height > 2 || height/width > 2 {
+			return fmt.Errorf("svg viewBox must be roughly square, got: %gx%g", width, height)
+		}
+		return nil
+	}
+	return errors.New("svg root element must have a viewBox")
+}
+
+// validateSvgAttr validates an attribute of an svg element.
+func validateSvgAttr(element string, attr xml.Attr) error {
+	name := strings.ToLower(attr.Name.Local)
+	value := strings.TrimSpace(attr.Value)
+	if strings.HasPrefix(name, "on") {
+		return fmt.Errorf("svg must not contain event handler attributes (%s on <%s>)", attr.Name.Local, element)
+	}
+	if name == "href" || name == "src" {
+		if !strings.HasPrefix(value, "#") {
+			return fmt.Errorf("svg must not contain external references (%s=%q on <%s>)", attr.Name.Local, attr.Value, element)
+		}
+	}
+	if name == "attributename" {
+		target := strings.ToLower(value)
+		if target == "href" || target == "src" || strings.HasSuffix(target, ":href") {
+			return fmt.Errorf("svg must not animate references (%s=%q on <%s>)", attr.Name.Local, attr.Value, element)
+		}
+	}
+	// the URIs are compared without the whitespace and the control characters the browsers ignore in them.
+	compact := strings.ToLower(strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, value))
+	if strings.Contains(compact, "javascript:") {
+		return fmt.Errorf("svg must not contain javascript: URIs (%s on <%s>)", attr.Name.Local, element)
+	}
+	if _, ok := svgAnimationValueAttrs[name]; ok {
+		for _, v := range strings.Split(compact, ";") {
+			if strings.Contains(v, ":") || strings.Contains(v, "/") {
+				return fmt.Errorf("svg must not contain external references (%s=%q on <%s>)", attr.Name.Local, attr.Value, element)
+			}
+		}
+	}
+	if strings.Contains(compact, "data:") {
+		return fmt.Errorf("svg must not contain embedded data (%s on <%s>)", attr.Name.Local, element)
+	}
+	return validateSvgStyle(value)
+}
+
+// validateSvgStyle validates that the css has no escapes and only references the fragments of the document.
+func validateSvgStyle(css string) error {
+	lower := strings.ToLower(css)
+	// the css escapes (e.g., u\72l() hide the functions and the at-rules from the checks below.
+	if strings.Contains(lower, "\\") {
+		return errors.New("svg must not contain css escapes (backslashes)")
+	}
+	if strings.Contains(lower, "@import") {
+		return errors.New("svg must not contain css @import")
+	}
+	if strings.Contains(lower, "data:") {
+		return errors.New("svg must not contain embedded data")
+	}
+	for rest := lower; ; {
+		i := strings.Index(rest, "url(")
+		if i < 0 {
+			return nil
+		}
+		rest = rest[i+len("url("):]
+		target := strings.TrimLeft(rest, " \t\n'\"")
+		if !strings.HasPrefix(target, "#") {
+			return errors.New("svg must not contain external references in url()")
+		}
+	}
+}
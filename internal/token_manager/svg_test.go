@@ -0,0 +1,51 @@
+package tokenmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateSvgFile(t *testing.T) {
+	const open = `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 32 32">`
+	tests := []struct {
+		name    string
+		svg     string
+		wantErr bool
+	}{
+		{"valid", open + `<circle cx="16" cy="16" r="16" fill="#000"/></svg>`, false},
+		{"fragment reference", open + `<defs><linearGradient id="g"/></defs><use href="#a"/><rect width="32" height="32" fill="url(#g)"/></svg>`, false},
+		{"color matrix values", open + `<filter id="f"><feColorMatrix values="1 0 0 0 0 0 1 0 0 0 0 0 1 0 0 0 0 0 1 0"/></filter><rect width="32" height="32"/></svg>`, false},
+		{"script", open + `<script>alert(1)</script><rect width="32" height="32"/></svg>`, true},
+		{"event handler", open + `<rect width="32" height="32" onclick="alert(1)"/></svg>`, true},
+		{"external href", open + `<use href="https://evil.example/x.svg#a"/></svg>`, true},
+		{"external xlink:href", open + `<use xlink:href="https://evil.example/x.svg#a"/></svg>`, true},
+		{"set href", open + `<a><set attributeName="href" to="https://evil.example/x.svg#a"/><rect width="32" height="32"/></a></svg>`, true},
+		{"animate href", open + `<a><animate attributeName="href" values="javascript:alert(1)"/><rect width="32" height="32"/></a></svg>`, true},
+		{"animate xlink:href", open + `<a><animate attributeName="xlink:href" values="#a;#b"/><rect width="32" height="32"/></a></svg>`, true},
+		{"animate motion", open + `<rect width="32" height="32"><animateMotion path="M0,0 L10,10"/></rect></svg>`, true},
+		{"animate transform", open + `<rect width="32" height="32"><animateTransform attributeName="transform" type="rotate" from="0" to="360"/></rect></svg>`, true},
+		{"javascript uri", open + `<a href="java&#x09;script:alert(1)"><rect width="32" height="32"/></a></svg>`, true},
+		{"data uri", open + `<rect width="32" height="32" fill="url(data:image/png;base64,AAAA)"/></svg>`, true},
+		{"external url", open + `<rect width="32" height="32" fill="url(https://evil.example/t)"/></svg>`, true},
+		{"css escape in style attribute", open + `<rect width="32" height="32" style="fill:u\72l(https://evil.example/t)"/></svg>`, true},
+		{"css escape in style element", open + `<style>rect{fill:u\72l(https://evil.example/t)}</style><rect width="32" height="32"/></svg>`, true},
+		{"css import", open + `<style>@import "https://evil.example/t.css";</style><rect width="32" height="32"/></svg>`, true},
+		{"entity declaration", `<!DOCTYPE svg [<!ENTITY x "y">]>` + open + `<rect width="32" height="32"/></svg>`, true},
+		{"no shapes", open + `<g/></svg>`, true},
+		{"no viewBox", `<svg xmlns="http://www.w3.org/2000/svg"><rect width="32" height="32"/></svg>`, true},
+	}
+	tm := &tokenManager{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "logo.svg")
+			if err := os.WriteFile(path, []byte(tt.svg), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := tm.validateSvgFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSvgFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package tokenmanager
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// exchangeDirs atomically exchanges the src and dst directories with renameat2(RENAME_EXCHANGE).
+// it returns false if the kernel or the filesystem does not support the exchange.
+func exchangeDirs(src, dst string) (bool, error) {
+	err := unix.Renameat2(unix.AT_FDCWD, src, unix.AT_FDCWD, dst, unix.RENAME_EXCHANGE)
+	if errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EINVAL) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
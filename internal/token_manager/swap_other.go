@@ -0,0 +1,8 @@
+//go:build !linux
+
+package tokenmanager
+
+// exchangeDirs reports that the atomic exchange of the directories is not supported on the platform.
+func exchangeDirs(src, dst string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,123 @@
+package tokenmanager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// tagsPath is the path of the tag definitions in the source tree.
+const tagsPath = "tags/tags.json"
+
+// tagsDistPath is the path of the tag definitions relative to the dist directory,
+// next to the tags directory so that no tag id is reserved.
+const tagsDistPath = "tags.json"
+
+// loadTags loads the tag definitions from tags/tags.json.
+// the invalid tag definitions are loaded as is and reported by ValidateTags.
+func (tm *tokenManager) loadTags(ctx context.Context) error {
+	bytes, err := os.ReadFile(tagsPath)
+	if err != nil {
+		return err
+	}
+	var tags models.Tags
+	if err := decodeStrict(bytes, &tags); err != nil {
+		return fmt.Errorf("%s: %w", tagsPath, err)
+	}
+	tm.tagDefinitions = tags.Tags
+	for _, tag := range tags.Tags {
+		if _, ok := tm.tags[tag.Id]; !ok {
+			tm.tags[tag.Id] = tag
+		}
+	}
+	return nil
+}
+
+// ValidateTags validates the tag definitions in the memory.
+// Validation rules:
+// - id, name, description and category are valid, refer to models.Tag.Validate.
+// - id is defined once, and the definitions are sorted by the id.
+// - the stablecoin tag is defined, refer to models.StablecoinTag.
+func (tm *tokenManager) ValidateTags(ctx context.Context) map[string][]error {
+	validationErrors := make(map[string][]error)
+	seen := make(map[string]struct{})
+	for i, tag := range tm.tagDefinitions {
+		key := tag.Id
+		if key == "" {
+			key = fmt.Sprintf("tags[%d]", i)
+		}
+		if err := tag.Validate(); err != nil {
+			validationErrors[key] = append(validationErrors[key], err)
+		}
+		if _, ok := seen[tag.Id]; ok {
+			validationErrors[key] = append(validationErrors[key], fmt.Errorf("tag is defined more than once"))
+		}
+		seen[tag.Id] = struct{}{}
+		if i > 0 && tm.tagDefinitions[i-1].Id > tag.Id {
+			validationErrors[key] = append(validationErrors[key], fmt.Errorf("tag must be sorted by the id, it comes after %q", tm.tagDefinitions[i-1].Id))
+		}
+	}
+	if _, ok := tm.tags[models.StablecoinTag]; !ok {
+		validationErrors[models.StablecoinTag] = append(validationErrors[models.StablecoinTag],
+			fmt.Errorf("tag is not defined, the stable tokens are required to have it"))
+	}
+	return validationErrors
+}
+
+// validateTokenTags validates the tags of the token:
+// - every tag is defined in tags/tags.json, and listed once.
+// - the token has the stablecoin tag if and only if it is a stable token.
+func (tm *tokenManager) validateTokenTags(tokenUid string) []error {
+	token := tm.tokens[tokenUid]
+	var errors []error
+	for i, tag := range token.Tags {
+		if _, ok := tm.tags[tag]; !ok {
+			errors = append(errors, fmt.Errorf("tags[%d]: unknown tag %q, the tags are defined in %s", i, tag, tagsPath))
+		}
+		if slices.Index(token.Tags, tag) < i {
+			errors = append(errors, fmt.Errorf("tags[%d]: tag %q is listed more than once", i, tag))
+		}
+	}
+	hasStablecoinTag := slices.Contains(token.Tags, models.StablecoinTag)
+	if token.IsStableToken && !hasStablecoinTag {
+		errors = append(errors, fmt.Errorf("stable token must have the %q tag", models.StablecoinTag))
+	}
+	if !token.IsStableToken && hasStablecoinTag {
+		errors = append(errors, fmt.Errorf("token with the %q tag must be a stable token", models.StablecoinTag))
+	}
+	return errors
+}
+
+// writeTags writes tags.json with the tag definitions and tags/:tag.json with the tokens of every tag.
+func (tm *tokenManager) writeTags(ctx context.Context, w *distWriter, tokens map[string]*models.Token) error {
+	tags := models.Tags{Tags: []models.Tag{}}
+	for _, tagId := range sortedKeys(tm.tags) {
+		tags.Tags = append(tags.Tags, tm.tags[tagId])
+	}
+	err := w.writeJSON(tagsDistPath, tags)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags.Tags {
+		index := models.TagIndex{Tag: tag, Tokens: []models.TaggedToken{}}
+		for _, tokenUid := range sortedTokenUids(tokens) {
+			token := tokens[tokenUid]
+			if slices.Contains(token.Tags, tag.Id) {
+				index.Tokens = append(index.Tokens, models.TaggedToken{
+					TokenUuid: tokenUid,
+					Symbol:    token.Symbol,
+					Name:      token.Name,
+				})
+			}
+		}
+		err := w.writeJSON(path.Join("tags", tag.Id+".json"), index)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
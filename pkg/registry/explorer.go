@@ -0,0 +1,29 @@
+package registry
+
+import (
+	"fmt"
+
+	"github.com/ma3xco/token-listing/internal/models"
+)
+
+// ExplorerKind is the kind of the explorer page.
+type ExplorerKind = models.ExplorerKind
+
+// The kinds of the explorer pages.
+const (
+	ExplorerAddress     = models.ExplorerKind_ADDRESS
+	ExplorerTransaction = models.ExplorerKind_TRANSACTION
+	ExplorerToken       = models.ExplorerKind_TOKEN
+	ExplorerBlock       = models.ExplorerKind_BLOCK
+)
+
+// ExplorerURL returns the URL of the explorer page of the value (an address, a transaction hash,
+// a token address or a block) on the network.
+// it returns an error if the network is not found or has no explorer page of the kind.
+func (c *Client) ExplorerURL(networkId int64, kind ExplorerKind, value string) (string, error) {
+	network, ok := c.Network(networkId)
+	if !ok {
+		return "", fmt.Errorf("network %d not found", networkId)
+	}
+	return network.ExplorerURL(kind, value)
+}
@@ -0,0 +1,318 @@
+// Package registry is the client of the published token registry.
+//
+// A Client loads the registry artifacts from the CDN (or any URL the dist
+// directory is deployed to) or from a local dist directory, verifies them
+// against manifest.json, optionally verifies the manifest signature with the
+// pinned public key, and answers the typed lookups from memory:
+//
+//	client, err := registry.New(ctx, "https://ma3xco.github.io/token-listing",
+//		registry.WithPublicKey(publicKey))
+//	token, ok := client.ByAddress(2, "0xdAC17F958D2ee523a2206206994597C13D831ec7")
+//
+// Refresh reloads the registry, the HTTP responses are cached with their
+// ETags, so an unchanged registry costs a single 304 response.
+package registry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/ma3xco/token-listing/pkg/signing"
+)
+
+// The models of the registry, they are the same types the registry is built from.
+type (
+	Token        = models.Token
+	TokenAddress = models.TokenAddress
+	LogoSet      = models.LogoSet
+	Network      = models.Network
+	Explorer     = models.Explorer
+	NetworkType  = models.NetworkType
+	Manifest     = models.Manifest
+)
+
+// The paths of the artifacts the client loads, relative to the root of the registry.
+const (
+	ManifestPath = "manifest.json"
+	TokensPath   = "tokens.json"
+	NetworksPath = "networks.json"
+)
+
+// ErrRollback is returned when the registry version is lower than the version already loaded.
+var ErrRollback = errors.New("registry version is lower than the loaded version")
+
+// Client is a client of the registry, it is safe for concurrent use.
+type Client struct {
+	source    source
+	publicKey ed25519.PublicKey
+
+	mu       sync.RWMutex
+	snapshot *snapshot
+}
+
+// Option configures the client.
+type Option func(*options)
+
+type options struct {
+	httpClient *http.Client
+	publicKey  ed25519.PublicKey
+}
+
+// WithHTTPClient sets the HTTP client the artifacts are fetched with.
+// default is http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithPublicKey sets the pinned public key of the registry,
+// manifest.json is only accepted with a valid manifest.json.sig.
+func WithPublicKey(publicKey ed25519.PublicKey) Option {
+	return func(o *options) {
+		o.publicKey = publicKey
+	}
+}
+
+// New returns a client of the registry at the location and loads the registry.
+// the location is an http(s) URL or a local dist directory.
+// it returns an error if any.
+func New(ctx context.Context, location string, opts ...Option) (*Client, error) {
+	o := options{httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	src, err := newSource(location, o.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{source: src, publicKey: o.publicKey}
+	if _, err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Refresh reloads the registry if the manifest changed, and reports whether it changed.
+// on error the previously loaded registry is kept.
+func (c *Client) Refresh(ctx context.Context) (bool, error) {
+	manifestBytes, err := c.source.fetch(ctx, ManifestPath)
+	if err != nil {
+		return false, err
+	}
+	current := c.current()
+	if current != nil && string(current.manifestBytes) == string(manifestBytes) {
+		return false, nil
+	}
+	if c.publicKey != nil {
+		signature, err := c.source.fetch(ctx, ManifestPath+signing.SignatureExt)
+		if err != nil {
+			return false, fmt.Errorf("cannot fetch the manifest signature: %w", err)
+		}
+		if err := signing.Verify(c.publicKey, manifestBytes, signature); err != nil {
+			return false, fmt.Errorf("cannot verify the manifest: %w", err)
+		}
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return false, fmt.Errorf("cannot decode the manifest: %w", err)
+	}
+	if current != nil && manifest.RegistryVersion < current.manifest.RegistryVersion {
+		return false, fmt.Errorf("%w: %d < %d", ErrRollback, manifest.RegistryVersion, current.manifest.RegistryVersion)
+	}
+
+	var tokens []Token
+	if err := c.fetchVerified(ctx, &manifest, TokensPath, &tokens); err != nil {
+		return false, err
+	}
+	var networks []Network
+	if err := c.fetchVerified(ctx, &manifest, NetworksPath, &networks); err != nil {
+		return false, err
+	}
+
+	next := newSnapshot(manifestBytes, manifest, tokens, networks)
+	c.mu.Lock()
+	c.snapshot = next
+	c.mu.Unlock()
+	return true, nil
+}
+
+// fetchVerified fetches the artifact, verifies its hash against the manifest and decodes it into v.
+func (c *Client) fetchVerified(ctx context.Context, manifest *Manifest, path string, v any) error {
+	var artifact *models.ManifestArtifact
+	for i := range manifest.Artifacts {
+		if manifest.Artifacts[i].Path == path {
+			artifact = &manifest.Artifacts[i]
+			break
+		}
+	}
+	if artifact == nil {
+		return fmt.Errorf("%s is not listed in the manifest", path)
+	}
+	body, err := c.source.fetch(ctx, path)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(body)
+	if int64(len(body)) != artifact.Size || hex.EncodeToString(sum[:]) != artifact.Sha256 {
+		return fmt.Errorf("%s does not match the manifest", path)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("cannot decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Manifest returns the manifest of the loaded registry.
+func (c *Client) Manifest() Manifest {
+	return c.current().manifest
+}
+
+// ByUID returns the token with the uid.
+// the returned token shares its slices with the client, it must not be modified.
+func (c *Client) ByUID(uid string) (Token, bool) {
+	s := c.current()
+	i, ok := s.byUID[uid]
+	if !ok {
+		return Token{}, false
+	}
+	return s.tokens[i], true
+}
+
+// ByAddress returns the token with the address on the network,
+// the addresses of the ethereum-like networks are case-insensitive.
+// the returned token shares its slices with the client, it must not be modified.
+func (c *Client) ByAddress(networkId int64, address string) (Token, bool) {
+	s := c.current()
+	network, ok := s.networkByID[networkId]
+	if !ok {
+		return Token{}, false
+	}
+	i, ok := s.byAddress[networkId][s.networks[network].CanonicalAddress(address)]
+	if !ok {
+		return Token{}, false
+	}
+	return s.tokens[i], true
+}
+
+// ByCAIP19 returns the token with the CAIP-19 asset id (e.g., "eip155:1/erc20:0xdac1...").
+// the returned token shares its slices with the client, it must not be modified.
+func (c *Client) ByCAIP19(assetId string) (Token, bool) {
+	s := c.current()
+	i, ok := s.byCaip19[assetId]
+	if !ok {
+		return Token{}, false
+	}
+	return s.tokens[i], true
+}
+
+// ByCMCID returns the token with the CoinMarketCap id.
+// the returned token shares its slices with the client, it must not be modified.
+func (c *Client) ByCMCID(id int64) (Token, bool) {
+	s := c.current()
+	i, ok := s.byCMCID[id]
+	if !ok {
+		return Token{}, false
+	}
+	return s.tokens[i], true
+}
+
+// Tokens returns all tokens sorted by the order index.
+// the returned tokens share their slices with the client, they must not be modified.
+func (c *Client) Tokens() []Token {
+	return append([]Token(nil), c.current().tokens...)
+}
+
+// Featured returns the featured tokens sorted by the order index.
+// the returned tokens share their slices with the client, they must not be modified.
+func (c *Client) Featured() []Token {
+	var featured []Token
+	for _, token := range c.current().tokens {
+		if token.IsFeatured {
+			featured = append(featured, token)
+		}
+	}
+	return featured
+}
+
+// Networks returns the networks sorted by the id.
+func (c *Client) Networks() []Network {
+	return append([]Network(nil), c.current().networks...)
+}
+
+// Network returns the network with the id.
+func (c *Client) Network(id int64) (Network, bool) {
+	s := c.current()
+	i, ok := s.networkByID[id]
+	if !ok {
+		return Network{}, false
+	}
+	return s.networks[i], true
+}
+
+func (c *Client) current() *snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshot
+}
+
+// snapshot is a loaded registry with its lookup indexes, it is never modified once built.
+type snapshot struct {
+	manifestBytes []byte
+	manifest      Manifest
+	tokens        []Token
+	networks      []Network
+
+	// the values are the indexes of the tokens and networks.
+	byUID       map[string]int
+	byAddress   map[int64]map[string]int
+	byCMCID     map[int64]int
+	byCaip19    map[string]int
+	networkByID map[int64]int
+}
+
+func newSnapshot(manifestBytes []byte, manifest Manifest, tokens []Token, networks []Network) *snapshot {
+	s := &snapshot{
+		manifestBytes: manifestBytes,
+		manifest:      manifest,
+		tokens:        tokens,
+		networks:      networks,
+		byUID:         make(map[string]int, len(tokens)),
+		byAddress:     make(map[int64]map[string]int),
+		byCMCID:       make(map[int64]int),
+		byCaip19:      make(map[string]int),
+		networkByID:   make(map[int64]int, len(networks)),
+	}
+	for i, network := range networks {
+		s.networkByID[network.Id] = i
+	}
+	for i, token := range tokens {
+		s.byUID[token.Uuid] = i
+		if _, ok := s.byCMCID[token.CoinMarketCapId]; !ok && token.CoinMarketCapId > 0 {
+			s.byCMCID[token.CoinMarketCapId] = i
+		}
+		for _, address := range token.Addresses {
+			networkId := int64(address.NetworkId)
+			canonical := address.Address
+			if n, ok := s.networkByID[networkId]; ok {
+				canonical = networks[n].CanonicalAddress(address.Address)
+			}
+			if s.byAddress[networkId] == nil {
+				s.byAddress[networkId] = make(map[string]int)
+			}
+			s.byAddress[networkId][canonical] = i
+			if address.Caip19 != "" {
+				s.byCaip19[address.Caip19] = i
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,244 @@
+package registry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	"github.com/ma3xco/token-listing/pkg/signing"
+)
+
+// testRegistry serves a dist directory from memory, with the ETags and the If-None-Match revalidation of a CDN.
+type testRegistry struct {
+	mu          sync.Mutex
+	files       map[string][]byte
+	notModified int
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, ok := r.files[strings.TrimPrefix(req.URL.Path, "/")]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	if req.Header.Get("If-None-Match") == etag {
+		r.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+// publish replaces the served dist with the dist of the version, signed with the private key.
+func (r *testRegistry) publish(t *testing.T, version int64, tokens []Token, privateKey ed25519.PrivateKey) {
+	t.Helper()
+	networks := []Network{
+		{Id: 2, Name: "Ethereum", NetworkType: models.NetworkType_NETWORK_TYPE_ETH_LIKE, ChainId: 1},
+		{Id: 4, Name: "Solana", NetworkType: models.NetworkType_NETWORK_TYPE_SOL},
+	}
+	files := map[string][]byte{
+		TokensPath:   mustMarshal(t, tokens),
+		NetworksPath: mustMarshal(t, networks),
+	}
+	manifest := Manifest{RegistryVersion: version, BuildTime: "2026-01-01T00:00:00Z"}
+	for _, path := range []string{NetworksPath, TokensPath} {
+		sum := sha256.Sum256(files[path])
+		manifest.Artifacts = append(manifest.Artifacts, models.ManifestArtifact{
+			Path:        path,
+			Sha256:      hex.EncodeToString(sum[:]),
+			Size:        int64(len(files[path])),
+			ContentType: "application/json",
+		})
+	}
+	files[ManifestPath] = mustMarshal(t, manifest)
+	files[ManifestPath+signing.SignatureExt] = signing.Sign(privateKey, files[ManifestPath])
+
+	r.mu.Lock()
+	r.files = files
+	r.mu.Unlock()
+}
+
+// replace replaces the content of a served file.
+func (r *testRegistry) replace(path string, body []byte) {
+	r.mu.Lock()
+	r.files[path] = body
+	r.mu.Unlock()
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes
+}
+
+func testTokens(symbols ...string) []Token {
+	var tokens []Token
+	for _, symbol := range symbols {
+		tokens = append(tokens, Token{Uuid: strings.ToLower(symbol), Symbol: symbol})
+	}
+	return tokens
+}
+
+func newTestRegistry(t *testing.T, tokens []Token) (*testRegistry, *httptest.Server, ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &testRegistry{}
+	r.publish(t, 1, tokens, privateKey)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return r, server, publicKey, privateKey
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	r, server, publicKey, privateKey := newTestRegistry(t, testTokens("USDT"))
+	client, err := New(ctx, server.URL, WithPublicKey(publicKey), WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	changed, err := client.Refresh(ctx)
+	if err != nil || changed {
+		t.Fatalf("Refresh() of an unchanged registry = %v, %v, want false, nil", changed, err)
+	}
+	if r.notModified != 1 {
+		t.Errorf("unchanged manifest was fetched with %d 304 responses, want 1", r.notModified)
+	}
+
+	r.publish(t, 2, testTokens("USDT", "USDC"), privateKey)
+	changed, err = client.Refresh(ctx)
+	if err != nil || !changed {
+		t.Fatalf("Refresh() of a new version = %v, %v, want true, nil", changed, err)
+	}
+	if _, ok := client.ByUID("usdc"); !ok {
+		t.Errorf("ByUID() did not find the token of the new version")
+	}
+	if got := client.Manifest().RegistryVersion; got != 2 {
+		t.Errorf("Manifest().RegistryVersion = %d, want 2", got)
+	}
+}
+
+func TestRefreshRejectsBadSignature(t *testing.T) {
+	ctx := context.Background()
+	r, server, publicKey, privateKey := newTestRegistry(t, testTokens("USDT"))
+
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.replace(ManifestPath+signing.SignatureExt, signing.Sign(otherKey, r.files[ManifestPath]))
+	if _, err := New(ctx, server.URL, WithPublicKey(publicKey)); !errors.Is(err, signing.ErrInvalidSignature) {
+		t.Fatalf("New() with a bad signature error = %v, want %v", err, signing.ErrInvalidSignature)
+	}
+
+	r.publish(t, 1, testTokens("USDT"), privateKey)
+	client, err := New(ctx, server.URL, WithPublicKey(publicKey))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.publish(t, 2, testTokens("USDT", "USDC"), otherKey)
+	if _, err := client.Refresh(ctx); !errors.Is(err, signing.ErrInvalidSignature) {
+		t.Errorf("Refresh() with a bad signature error = %v, want %v", err, signing.ErrInvalidSignature)
+	}
+	if _, ok := client.ByUID("usdc"); ok {
+		t.Errorf("Refresh() loaded the tokens of a manifest with a bad signature")
+	}
+}
+
+func TestRefreshRejectsRollback(t *testing.T) {
+	ctx := context.Background()
+	r, server, publicKey, privateKey := newTestRegistry(t, testTokens("USDT"))
+	r.publish(t, 5, testTokens("USDT", "USDC"), privateKey)
+	client, err := New(ctx, server.URL, WithPublicKey(publicKey))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	r.publish(t, 4, testTokens("USDT"), privateKey)
+	if _, err := client.Refresh(ctx); !errors.Is(err, ErrRollback) {
+		t.Fatalf("Refresh() of an older version error = %v, want %v", err, ErrRollback)
+	}
+	if got := client.Manifest().RegistryVersion; got != 5 {
+		t.Errorf("Manifest().RegistryVersion after a rollback = %d, want 5", got)
+	}
+	if _, ok := client.ByUID("usdc"); !ok {
+		t.Errorf("Refresh() dropped the loaded tokens after a rollback")
+	}
+}
+
+func TestRefreshRejectsHashMismatch(t *testing.T) {
+	ctx := context.Background()
+	r, server, publicKey, privateKey := newTestRegistry(t, testTokens("USDT"))
+	client, err := New(ctx, server.URL, WithPublicKey(publicKey))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	r.publish(t, 2, testTokens("USDT", "USDC"), privateKey)
+	r.replace(TokensPath, mustMarshal(t, testTokens("USDT", "USDX")))
+	if _, err := client.Refresh(ctx); err == nil || !strings.Contains(err.Error(), "does not match the manifest") {
+		t.Fatalf("Refresh() of a tampered artifact error = %v, want a manifest mismatch", err)
+	}
+	if _, ok := client.ByUID("usdx"); ok {
+		t.Errorf("Refresh() loaded the tokens of a tampered artifact")
+	}
+	if got := client.Manifest().RegistryVersion; got != 1 {
+		t.Errorf("Manifest().RegistryVersion after a failed refresh = %d, want 1", got)
+	}
+}
+
+func TestByAddress(t *testing.T) {
+	tokens := []Token{
+		{Uuid: "usdt", Symbol: "USDT", Addresses: []TokenAddress{
+			{NetworkId: 2, Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7"},
+		}},
+		{Uuid: "usdc", Symbol: "USDC", Addresses: []TokenAddress{
+			{NetworkId: 4, Address: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"},
+		}},
+	}
+	_, server, _, _ := newTestRegistry(t, tokens)
+	client, err := New(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		networkId int64
+		address   string
+		wantUid   string
+	}{
+		{2, "0xdAC17F958D2ee523a2206206994597C13D831ec7", "usdt"},
+		{2, "0xdac17f958d2ee523a2206206994597c13d831ec7", "usdt"},
+		{2, " 0xDAC17F958D2EE523A2206206994597C13D831EC7 ", "usdt"},
+		{4, "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", "usdc"},
+		// the addresses of the other networks are case-sensitive.
+		{4, "epjfwdd5aufqssqem2qn1xzybapc8g4wegGkzwytdt1v", ""},
+		{3, "0xdAC17F958D2ee523a2206206994597C13D831ec7", ""},
+	}
+	for _, tt := range tests {
+		token, ok := client.ByAddress(tt.networkId, tt.address)
+		if ok != (tt.wantUid != "") || token.Uuid != tt.wantUid {
+			t.Errorf("ByAddress(%d, %q) = %q, %v, want %q", tt.networkId, tt.address, token.Uuid, ok, tt.wantUid)
+		}
+	}
+}
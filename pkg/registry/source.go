@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// maxArtifactSize is the size limit of a fetched artifact.
+const maxArtifactSize = 64 << 20
+
+// source fetches the registry artifacts.
+type source interface {
+	// fetch returns the content of the artifact at the path relative to the root of the registry.
+	fetch(ctx context.Context, path string) ([]byte, error)
+}
+
+// newSource returns the source of the location, an http(s) URL or a local directory.
+func newSource(location string, httpClient *http.Client) (source, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		base, err := url.Parse(strings.TrimSuffix(location, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry URL: %w", err)
+		}
+		return &httpSource{base: base, client: httpClient, cache: make(map[string]cachedResponse)}, nil
+	}
+	info, err := os.Stat(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("registry location %s is not a directory", location)
+	}
+	return dirSource(location), nil
+}
+
+// dirSource reads the artifacts from a local dist directory.
+type dirSource string
+
+func (d dirSource) fetch(ctx context.Context, path string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(string(d), filepath.FromSlash(path)))
+}
+
+// httpSource fetches the artifacts over HTTP, the responses are cached with their ETags
+// and revalidated with If-None-Match.
+type httpSource struct {
+	base   *url.URL
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+func (h *httpSource) fetch(ctx context.Context, path string) ([]byte, error) {
+	u := h.base.JoinPath(path).String()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	cached, ok := h.cache[path]
+	h.mu.Unlock()
+	if ok {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		return cached.body, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("cannot fetch %s: %s", u, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxArtifactSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", u, err)
+	}
+	if len(body) > maxArtifactSize {
+		return nil, errors.New("artifact " + path + " is too large")
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		h.mu.Lock()
+		h.cache[path] = cachedResponse{etag: etag, body: body}
+		h.mu.Unlock()
+	}
+	return body, nil
+}
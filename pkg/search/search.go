@@ -0,0 +1,283 @@
+// Package search builds and queries the token search index of the registry.
+//
+// The registry build publishes search_index.json, a compact index over the
+// symbol, the name and the addresses of every listed token, so the clients
+// can search the tokens without downloading tokens.json.
+//
+// The symbols and the names are indexed by their trigrams, which finds the
+// prefix matches and tolerates typos. The results are ranked by the match kind
+// (an exact symbol match first), then featured tokens first, then by the
+// similarity and the order index of the tokens.
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Version is the version of the index format.
+const Version = 1
+
+// minSimilarity is the share of the query trigrams a fuzzy match must contain.
+const minSimilarity = 0.5
+
+// Index is the search index.
+type Index struct {
+	// The version of the index format.
+	Version int `json:"version"`
+
+	// The indexed tokens, sorted by the order index.
+	Tokens []Entry `json:"tokens"`
+
+	// The trigrams of the lowercased symbols and names,
+	// the value is the indices of the tokens containing the trigram.
+	Trigrams map[string][]int `json:"trigrams"`
+}
+
+// Entry is an indexed token.
+type Entry struct {
+	// The uuid of the token.
+	Uid string `json:"uid"`
+
+	// The symbol of the token.
+	Symbol string `json:"symbol"`
+
+	// The name of the token.
+	Name string `json:"name"`
+
+	// The order index of the token, the lower the index, the higher the priority.
+	OrderIndex int64 `json:"order_index"`
+
+	// Whether the token is featured.
+	Featured bool `json:"featured,omitempty"`
+
+	// The addresses of the token.
+	Addresses []Address `json:"addresses,omitempty"`
+}
+
+// Address is an indexed token address.
+type Address struct {
+	// The id of the network.
+	NetworkId int64 `json:"network_id"`
+
+	// The address of the token on the network.
+	Address string `json:"address"`
+}
+
+// Match is the kind of a match, the lower the better.
+type Match int
+
+const (
+	MatchExactSymbol Match = iota
+	MatchExactAddress
+	MatchSymbolPrefix
+	MatchExactName
+	MatchNamePrefix
+	MatchNameWordPrefix
+	MatchAddressPrefix
+	MatchFuzzy
+)
+
+var matchNames = map[Match]string{
+	MatchExactSymbol:    "exact_symbol",
+	MatchExactAddress:   "exact_address",
+	MatchSymbolPrefix:   "symbol_prefix",
+	MatchExactName:      "exact_name",
+	MatchNamePrefix:     "name_prefix",
+	MatchNameWordPrefix: "name_word_prefix",
+	MatchAddressPrefix:  "address_prefix",
+	MatchFuzzy:          "fuzzy",
+}
+
+func (m Match) String() string {
+	if name, ok := matchNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("match(%d)", int(m))
+}
+
+// MarshalText marshals the match as its name.
+func (m Match) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// Result is a search result.
+type Result struct {
+	// The matched token.
+	Entry Entry `json:"entry"`
+
+	// The kind of the match.
+	Match Match `json:"match"`
+
+	// The share of the query trigrams found in the symbol or the name, between 0 and 1.
+	Similarity float64 `json:"similarity"`
+}
+
+// Build builds the index of the entries, the entries are sorted by the order index and then by the uid.
+func Build(entries []Entry) *Index {
+	idx := &Index{
+		Version:  Version,
+		Tokens:   append(make([]Entry, 0, len(entries)), entries...),
+		Trigrams: make(map[string][]int),
+	}
+	sort.SliceStable(idx.Tokens, func(i, j int) bool {
+		if idx.Tokens[i].OrderIndex != idx.Tokens[j].OrderIndex {
+			return idx.Tokens[i].OrderIndex < idx.Tokens[j].OrderIndex
+		}
+		return idx.Tokens[i].Uid < idx.Tokens[j].Uid
+	})
+	for i, entry := range idx.Tokens {
+		for trigram := range entryTrigrams(entry) {
+			idx.Trigrams[trigram] = append(idx.Trigrams[trigram], i)
+		}
+	}
+	return idx
+}
+
+// Read reads an index in the JSON format.
+// it returns an error if any.
+func Read(r io.Reader) (*Index, error) {
+	var idx Index
+	if err := json.NewDecoder(r).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("cannot decode the search index: %w", err)
+	}
+	if idx.Version != Version {
+		return nil, fmt.Errorf("unsupported search index version: %d (supported: %d)", idx.Version, Version)
+	}
+	for trigram, postings := range idx.Trigrams {
+		for _, i := range postings {
+			if i < 0 || i >= len(idx.Tokens) {
+				return nil, fmt.Errorf("search index trigram %q points at token %d of %d", trigram, i, len(idx.Tokens))
+			}
+		}
+	}
+	return &idx, nil
+}
+
+// Search returns the tokens matching the query, best first.
+// the query matches the symbols, the names and the addresses case-insensitively.
+// limit caps the number of the results, 0 means no limit.
+func (idx *Index) Search(query string, limit int) []Result {
+	query = normalize(query)
+	if query == "" {
+		return nil
+	}
+
+	// the trigram postings give the similarity of every token for the fuzzy matches,
+	// the prefix and the address matches are checked on every token, which stays cheap
+	// for thousands of tokens.
+	queryTrigrams := trigrams(query)
+	shared := make(map[int]int)
+	for trigram := range queryTrigrams {
+		for _, i := range idx.Trigrams[trigram] {
+			shared[i]++
+		}
+	}
+
+	var results []Result
+	for i, entry := range idx.Tokens {
+		similarity := 0.0
+		if len(queryTrigrams) > 0 {
+			similarity = float64(shared[i]) / float64(len(queryTrigrams))
+		}
+		match, ok := matchEntry(entry, query, similarity)
+		if !ok {
+			continue
+		}
+		results = append(results, Result{Entry: entry, Match: match, Similarity: similarity})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Match != b.Match {
+			return a.Match < b.Match
+		}
+		if a.Entry.Featured != b.Entry.Featured {
+			return a.Entry.Featured
+		}
+		if a.Similarity != b.Similarity {
+			return a.Similarity > b.Similarity
+		}
+		if a.Entry.OrderIndex != b.Entry.OrderIndex {
+			return a.Entry.OrderIndex < b.Entry.OrderIndex
+		}
+		return a.Entry.Uid < b.Entry.Uid
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// matchEntry returns the best match of the normalized query on the entry.
+func matchEntry(entry Entry, query string, similarity float64) (Match, bool) {
+	symbol := normalize(entry.Symbol)
+	name := normalize(entry.Name)
+	best, found := MatchFuzzy, false
+	consider := func(match Match) {
+		if !found || match < best {
+			best, found = match, true
+		}
+	}
+
+	switch {
+	case symbol == query:
+		consider(MatchExactSymbol)
+	case strings.HasPrefix(symbol, query):
+		consider(MatchSymbolPrefix)
+	}
+	switch {
+	case name == query:
+		consider(MatchExactName)
+	case strings.HasPrefix(name, query):
+		consider(MatchNamePrefix)
+	default:
+		words := strings.Fields(name)
+		for i := 1; i < len(words); i++ {
+			if strings.HasPrefix(words[i], query) {
+				consider(MatchNameWordPrefix)
+				break
+			}
+		}
+	}
+	for _, address := range entry.Addresses {
+		a := strings.ToLower(address.Address)
+		switch {
+		case a == query:
+			consider(MatchExactAddress)
+		case len(query) >= 3 && strings.HasPrefix(a, query):
+			consider(MatchAddressPrefix)
+		}
+	}
+	if similarity >= minSimilarity {
+		consider(MatchFuzzy)
+	}
+	return best, found
+}
+
+// entryTrigrams returns the distinct trigrams of the symbol and the name of the entry.
+func entryTrigrams(entry Entry) map[string]struct{} {
+	set := trigrams(normalize(entry.Symbol))
+	for trigram := range trigrams(normalize(entry.Name)) {
+		set[trigram] = struct{}{}
+	}
+	return set
+}
+
+// trigrams returns the distinct trigrams of the text.
+func trigrams(text string) map[string]struct{} {
+	runes := []rune(text)
+	set := make(map[string]struct{})
+	for i := 0; i+3 <= len(runes); i++ {
+		set[string(runes[i:i+3])] = struct{}{}
+	}
+	return set
+}
+
+// normalize lowercases the text and collapses the whitespaces.
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
@@ -0,0 +1,199 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testEntries are the tokens of the test index.
+var testEntries = []Entry{
+	{Uid: "usdt", Symbol: "USDT", Name: "Tether USD", OrderIndex: 1, Featured: true, Addresses: []Address{
+		{NetworkId: 2, Address: "0xdac17f958d2ee523a2206206994597c13d831ec7"},
+		{NetworkId: 4, Address: "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB"},
+	}},
+	{Uid: "usdc", Symbol: "USDC", Name: "USD Coin", OrderIndex: 2, Featured: true, Addresses: []Address{
+		{NetworkId: 2, Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"},
+	}},
+	// the lowest order index, but not featured.
+	{Uid: "usde", Symbol: "USDe", Name: "Ethena USDe", OrderIndex: 0},
+	{Uid: "usd", Symbol: "USD", Name: "Decentralized USD", OrderIndex: 100},
+	{Uid: "eth", Symbol: "ETH", Name: "Ethereum", OrderIndex: 3, Featured: true},
+	{Uid: "weth", Symbol: "WETH", Name: "Wrapped Ether", OrderIndex: 4, Addresses: []Address{
+		{NetworkId: 2, Address: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"},
+	}},
+}
+
+// result is the uid and the match kind of a search result.
+type result struct {
+	uid   string
+	match Match
+}
+
+func TestSearch(t *testing.T) {
+	idx := Build(testEntries)
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []result
+	}{
+		{"exact symbol above featured above order index", "usd", 0, []result{
+			{"usd", MatchExactSymbol},
+			{"usdt", MatchSymbolPrefix},
+			{"usdc", MatchSymbolPrefix},
+			{"usde", MatchSymbolPrefix},
+		}},
+		{"case and whitespace insensitive", "  USDT ", 0, []result{
+			{"usdt", MatchExactSymbol},
+			// the fuzzy matches share half of the query trigrams (usd).
+			{"usdc", MatchFuzzy},
+			{"usde", MatchFuzzy},
+			{"usd", MatchFuzzy},
+		}},
+		{"exact symbol above name prefixes", "eth", 0, []result{
+			{"eth", MatchExactSymbol},
+			{"usde", MatchNamePrefix},
+			{"weth", MatchNameWordPrefix},
+			// "tether" contains the query.
+			{"usdt", MatchFuzzy},
+		}},
+		{"name prefix above name word prefix", "ether", 0, []result{
+			{"eth", MatchNamePrefix},
+			{"weth", MatchNameWordPrefix},
+			{"usdt", MatchFuzzy},
+			{"usde", MatchFuzzy},
+		}},
+		{"exact name", "wrapped ether", 0, []result{
+			{"weth", MatchExactName},
+		}},
+		// the featured fuzzy matches first, then by the similarity.
+		{"fuzzy typo", "ethereun", 0, []result{
+			{"eth", MatchFuzzy},
+			{"usdt", MatchFuzzy},
+			{"weth", MatchFuzzy},
+		}},
+		{"fuzzy below the similarity", "ethxxxxx", 0, nil},
+		{"limit", "usd", 2, []result{
+			{"usd", MatchExactSymbol},
+			{"usdt", MatchSymbolPrefix},
+		}},
+		{"empty", "   ", 0, nil},
+		{"no match", "bitcoin", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []result
+			for _, r := range idx.Search(tt.query, tt.limit) {
+				got = append(got, result{r.Entry.Uid, r.Match})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchAddress(t *testing.T) {
+	idx := Build(testEntries)
+	tests := []struct {
+		name  string
+		query string
+		want  []result
+	}{
+		{"exact evm address", "0xdac17f958d2ee523a2206206994597c13d831ec7", []result{{"usdt", MatchExactAddress}}},
+		{"checksummed evm address", "0xdAC17F958D2ee523a2206206994597C13D831ec7", []result{{"usdt", MatchExactAddress}}},
+		{"exact solana address", "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB", []result{{"usdt", MatchExactAddress}}},
+		{"address prefix", "0xc02a", []result{{"weth", MatchAddressPrefix}}},
+		{"shared address prefix", "0x", nil},
+		{"every evm address", "0xa0b8", []result{{"usdc", MatchAddressPrefix}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []result
+			for _, r := range idx.Search(tt.query, 0) {
+				got = append(got, result{r.Entry.Uid, r.Match})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	idx := Build(testEntries)
+	var uids []string
+	for _, entry := range idx.Tokens {
+		uids = append(uids, entry.Uid)
+	}
+	if want := []string{"usde", "usdt", "usdc", "eth", "weth", "usd"}; !reflect.DeepEqual(uids, want) {
+		t.Errorf("Build() tokens = %v, want the order index order %v", uids, want)
+	}
+	// the trigrams of the symbol and the name are indexed once per token.
+	if got := idx.Trigrams["usd"]; !reflect.DeepEqual(got, []int{0, 1, 2, 5}) {
+		t.Errorf("Build() trigram usd = %v, want [0 1 2 5]", got)
+	}
+	if testEntries[0].Uid != "usdt" {
+		t.Errorf("Build() modified the entries")
+	}
+}
+
+func TestReadRoundTrip(t *testing.T) {
+	idx := Build(testEntries)
+	bytes, err := json.Marshal(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(strings.NewReader(string(bytes)))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(read, idx) {
+		t.Errorf("Read(json.Marshal(idx)) = %+v, want %+v", read, idx)
+	}
+	for _, query := range []string{"usd", "ether", "ethereun", "0xc02a"} {
+		if got, want := read.Search(query, 0), idx.Search(query, 0); !reflect.DeepEqual(got, want) {
+			t.Errorf("Search(%q) of the read index = %v, want %v", query, got, want)
+		}
+	}
+	// the featured flag and the addresses are omitted when empty.
+	if strings.Contains(string(bytes), `"featured":false`) || strings.Contains(string(bytes), `"addresses":null`) {
+		t.Errorf("json.Marshal(idx) = %s, want the empty fields omitted", bytes)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"not json", `{"version": 1,`, "cannot decode the search index"},
+		{"unsupported version", `{"version": 2, "tokens": [], "trigrams": {}}`, "unsupported search index version: 2"},
+		{"posting out of range", `{"version": 1, "tokens": [{"uid": "a"}], "trigrams": {"abc": [0, 1]}}`, `trigram "abc" points at token 1 of 1`},
+		{"negative posting", `{"version": 1, "tokens": [], "trigrams": {"abc": [-1]}}`, `trigram "abc" points at token -1 of 0`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(strings.NewReader(tt.json)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Read() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(Result{Entry: Entry{Uid: "usdt"}, Match: MatchNameWordPrefix, Similarity: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"match":"name_word_prefix"`) {
+		t.Errorf("json of the result = %s, want the match name", buf.String())
+	}
+	if got := Match(42).String(); got != "match(42)" {
+		t.Errorf("Match(42).String() = %q, want match(42)", got)
+	}
+}
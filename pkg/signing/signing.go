@@ -0,0 +1,97 @@
+// Package signing signs and verifies the registry artifacts with ed25519.
+//
+// The registry build signs manifest.json (and optionally every artifact) and
+// publishes detached signatures next to the signed files, named <file>.sig.
+// A signature file contains the base64 (standard encoding) of the 64 bytes
+// ed25519 signature of the file content.
+//
+// Clients should pin the public key of the registry and verify manifest.json
+// with Verify before trusting the artifact hashes listed in it.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// SignatureExt is the extension of the detached signature files.
+	SignatureExt = ".sig"
+
+	// PublicKeyPath is the path of the published public key relative to the root of the registry.
+	PublicKeyPath = "signing_key.pem"
+)
+
+// ErrInvalidSignature is returned when the signature does not match the data.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// ParsePrivateKey parses a PEM encoded PKCS#8 ed25519 private key,
+// as generated by `openssl genpkey -algorithm ed25519`.
+func ParsePrivateKey(pemBytes []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM block found in the private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the private key: %v", err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is %T, not ed25519", key)
+	}
+	return privateKey, nil
+}
+
+// ParsePublicKey parses a PEM encoded PKIX ed25519 public key.
+func ParsePublicKey(pemBytes []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM block found in the public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the public key: %v", err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is %T, not ed25519", key)
+	}
+	return publicKey, nil
+}
+
+// MarshalPublicKey encodes the public key as a PEM encoded PKIX public key.
+func MarshalPublicKey(publicKey ed25519.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// Sign signs the data and returns the content of the detached signature file.
+func Sign(privateKey ed25519.PrivateKey, data []byte) []byte {
+	signature := ed25519.Sign(privateKey, data)
+	return []byte(base64.StdEncoding.EncodeToString(signature) + "\n")
+}
+
+// Verify verifies the data against the content of its detached signature file.
+// it returns ErrInvalidSignature if the signature does not match.
+func Verify(publicKey ed25519.PublicKey, data, signature []byte) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("public key must be %d bytes, got: %d", ed25519.PublicKeySize, len(publicKey))
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("cannot decode the signature: %v", err)
+	}
+	if len(raw) != ed25519.SignatureSize || !ed25519.Verify(publicKey, data, raw) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -2,15 +2,67 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
 
+	"github.com/ma3xco/token-listing/internal/changelog"
 	tokenmanager "github.com/ma3xco/token-listing/internal/token_manager"
+	"github.com/ma3xco/token-listing/pkg/signing"
 )
 
 func main() {
+	var signingKeyFile string
+	var signArtifacts bool
+	var previous string
+	var previousManifest string
+	var profiles string
+
+	flag.StringVar(&signingKeyFile, "signing-key", "", "Path of the PEM encoded ed25519 signing key, falls back to the REGISTRY_SIGNING_KEY environment variable")
+	flag.BoolVar(&signArtifacts, "sign-artifacts", false, "Whether every artifact is signed, not only the manifest")
+	flag.StringVar(&previous, "previous", "", "The previous registry state to build changelog.json against: git:<ref>, a dist directory or a tokens.json file, the enabled tokens are only reported if the blocklist.json is next to the tokens.json")
+	flag.StringVar(&previousManifest, "previous-manifest", "", "Path of the published manifest.json, the build fails if its registry version is lower than the published one")
+	flag.StringVar(&profiles, "profiles", string(tokenmanager.ProfileAll), "Comma-separated build profiles: all (published at the root of dist), mainnet and testnet (published into dist/<profile>)")
+	flag.Parse()
+
+	var ops []tokenmanager.Option
+	if signingKeyFile != "" {
+		ops = append(ops, tokenmanager.WithSigningKeyFile(signingKeyFile))
+	} else if pemKey := os.Getenv("REGISTRY_SIGNING_KEY"); pemKey != "" {
+		key, err := signing.ParsePrivateKey([]byte(pemKey))
+		if err != nil {
+			log.Fatalf("failed to parse the signing key: %v", err)
+		}
+		ops = append(ops, tokenmanager.WithSigningKey(key))
+	}
+	if signArtifacts {
+		if len(ops) == 0 {
+			log.Fatalf("-sign-artifacts requires a signing key")
+		}
+		ops = append(ops, tokenmanager.WithArtifactSignatures())
+	}
+
+	buildProfiles, err := tokenmanager.ParseProfiles(profiles)
+	if err != nil {
+		log.Fatalf("invalid -profiles: %v", err)
+	}
+	ops = append(ops, tokenmanager.WithProfiles(buildProfiles...))
+
+	if previousManifest != "" {
+		ops = append(ops, tokenmanager.WithPreviousManifestFile(previousManifest))
+	}
+
+	if previous != "" {
+		tokens, err := changelog.Load(context.Background(), previous)
+		if err != nil {
+			log.Fatalf("failed to load the previous registry state: %v", err)
+		}
+		ops = append(ops, tokenmanager.WithPreviousTokens(previous, tokens))
+	}
+
 	// build the tokens
-	tm, err := tokenmanager.New(context.Background())
+	tm, err := tokenmanager.New(context.Background(), ops...)
 	if err != nil {
 		log.Fatalf("failed to create token manager: %v", err)
 	}
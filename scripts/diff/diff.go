@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ma3xco/token-listing/internal/changelog"
+)
+
+func main() {
+	var from string
+	var to string
+	var format string
+	var output string
+
+	flag.StringVar(&from, "from", "", "The previous registry state: git:<ref>, a dist directory, a source tree or a tokens.json file")
+	flag.StringVar(&to, "to", ".", "The current registry state: git:<ref>, a dist directory, a source tree or a tokens.json file")
+	flag.StringVar(&format, "format", "markdown", "The output format: markdown or json")
+	flag.StringVar(&output, "out", "", "The output file, defaults to stdout")
+	flag.Parse()
+
+	if from == "" {
+		log.Fatalf("-from is required")
+	}
+	if format != "markdown" && format != "json" {
+		log.Fatalf("invalid format %q: must be markdown or json", format)
+	}
+
+	ctx := context.Background()
+	previous, err := changelog.Load(ctx, from)
+	if err != nil {
+		log.Fatalf("failed to load %s: %v", from, err)
+	}
+	current, err := changelog.Load(ctx, to)
+	if err != nil {
+		log.Fatalf("failed to load %s: %v", to, err)
+	}
+	c, err := changelog.Diff(previous, current)
+	if err != nil {
+		log.Fatalf("failed to diff the registry states: %v", err)
+	}
+	c.From = from
+	c.To = to
+
+	var bytes []byte
+	if format == "json" {
+		bytes, err = json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			log.Fatalf("failed to marshal the changelog: %v", err)
+		}
+		bytes = append(bytes, '\n')
+	} else {
+		bytes = []byte(c.Markdown())
+	}
+
+	if output == "" {
+		fmt.Print(string(bytes))
+		return
+	}
+	err = os.WriteFile(output, bytes, 0644)
+	if err != nil {
+		log.Fatalf("failed to write %s: %v", output, err)
+	}
+}
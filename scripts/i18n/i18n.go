@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ma3xco/token-listing/internal/models"
+	tokenmanager "github.com/ma3xco/token-listing/internal/token_manager"
+)
+
+func main() {
+	var locale string
+	var format string
+
+	flag.StringVar(&locale, "locale", "", "Report only the locale, all the locales if empty")
+	flag.StringVar(&format, "format", "text", "The output format: text or json")
+	flag.Parse()
+
+	if format != "text" && format != "json" {
+		log.Fatalf("invalid format %q: must be text or json", format)
+	}
+
+	tm, err := tokenmanager.New(context.Background())
+	if err != nil {
+		log.Fatalf("failed to create token manager: %v", err)
+	}
+	_, err = tm.WalkThrough(context.Background())
+	if err != nil {
+		log.Fatalf("failed to walk through tokens: %v", err)
+	}
+	coverage := tm.I18nCoverage(context.Background())
+	if locale != "" {
+		locales := []models.LocaleCoverage{}
+		for _, c := range coverage.Locales {
+			if c.Locale == locale {
+				locales = append(locales, c)
+			}
+		}
+		if len(locales) == 0 {
+			log.Fatalf("no translations found for locale %q", locale)
+		}
+		coverage.Locales = locales
+	}
+
+	if format == "json" {
+		bytes, err := json.MarshalIndent(coverage, "", "  ")
+		if err != nil {
+			log.Fatalf("failed to marshal the coverage: %v", err)
+		}
+		fmt.Println(string(bytes))
+		return
+	}
+
+	if len(coverage.Locales) == 0 {
+		fmt.Println("no translations found")
+		return
+	}
+	for _, c := range coverage.Locales {
+		fmt.Printf("%s: %d/%d names, %d/%d descriptions\n", c.Locale, c.Names, coverage.Tokens, c.Descriptions, coverage.Tokens)
+		for _, missing := range c.Missing {
+			fmt.Printf("  %s (%s): missing %s\n", missing.TokenUuid, missing.Symbol, strings.Join(missing.Fields, ", "))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	tokenmanager "github.com/ma3xco/token-listing/internal/token_manager"
+	"github.com/ma3xco/token-listing/pkg/search"
+)
+
+func main() {
+	var indexFile string
+	var limit int
+
+	flag.StringVar(&indexFile, "index", "", "Path of a search_index.json, the index is built from the source tree if empty")
+	flag.IntVar(&limit, "limit", 10, "The maximum number of the results, 0 means no limit")
+	flag.Parse()
+
+	query := strings.Join(flag.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		log.Fatalf("usage: search [-index search_index.json] [-limit 10] <query>")
+	}
+
+	var idx *search.Index
+	if indexFile != "" {
+		file, err := os.Open(indexFile)
+		if err != nil {
+			log.Fatalf("failed to open the search index: %v", err)
+		}
+		defer file.Close()
+		idx, err = search.Read(file)
+		if err != nil {
+			log.Fatalf("failed to read the search index: %v", err)
+		}
+	} else {
+		tm, err := tokenmanager.New(context.Background())
+		if err != nil {
+			log.Fatalf("failed to create token manager: %v", err)
+		}
+		_, err = tm.WalkThrough(context.Background())
+		if err != nil {
+			log.Fatalf("failed to walk through tokens: %v", err)
+		}
+		idx = tm.SearchIndex(context.Background())
+	}
+
+	results := idx.Search(query, limit)
+	if len(results) == 0 {
+		fmt.Println("no tokens found")
+		return
+	}
+	for _, result := range results {
+		featured := ""
+		if result.Entry.Featured {
+			featured = " (featured)"
+		}
+		fmt.Printf("%-10s %-30s %s  %s%s\n", result.Entry.Symbol, result.Entry.Name, result.Entry.Uid, result.Match, featured)
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/ma3xco/token-listing/internal/onchain"
+	"github.com/ma3xco/token-listing/internal/pricefeed"
 	tokenmanager "github.com/ma3xco/token-listing/internal/token_manager"
 )
 
@@ -15,13 +19,39 @@ func main() {
 	var isFork bool
 	var hasScriptTag bool
 	var changedFiles string
+	var checkPriceUrls bool
+	var priceTimeout time.Duration
+	var priceMaxAge time.Duration
+	var verifyOnchain bool
+	var rpcConfig string
+	var rpcTimeout time.Duration
 
 	flag.BoolVar(&isFork, "fork", false, "Whether the PR is from a fork")
 	flag.BoolVar(&hasScriptTag, "script", false, "Whether the PR has a script tag")
 	flag.StringVar(&changedFiles, "files", "", "Comma-separated list of changed files")
+	flag.BoolVar(&checkPriceUrls, "check-price-urls", false, "Fetch the live price urls and validate the responses against the price contract")
+	flag.DurationVar(&priceTimeout, "price-timeout", pricefeed.DefaultTimeout, "The time limit of a live price url check")
+	flag.DurationVar(&priceMaxAge, "price-max-age", pricefeed.DefaultMaxAge, "The age above which a live price is reported as stale")
+	flag.BoolVar(&verifyOnchain, "verify-onchain", false, "Verify the ERC-20 addresses against their contracts over JSON-RPC")
+	flag.StringVar(&rpcConfig, "rpc-config", "rpc.json", "The JSON file with the RPC url by the network id, for -verify-onchain")
+	flag.DurationVar(&rpcTimeout, "rpc-timeout", onchain.DefaultTimeout, "The time limit of the verification of an address")
 	flag.Parse()
 
-	tm, err := tokenmanager.New(context.Background())
+	var ops []tokenmanager.Option
+	if checkPriceUrls {
+		ops = append(ops, tokenmanager.WithPriceChecker(pricefeed.New(
+			pricefeed.WithTimeout(priceTimeout),
+			pricefeed.WithMaxAge(priceMaxAge),
+		)))
+	}
+	if verifyOnchain {
+		rpcUrls, err := onchain.LoadConfig(rpcConfig)
+		if err != nil {
+			log.Fatalf("failed to load the rpc config: %v", err)
+		}
+		ops = append(ops, tokenmanager.WithOnchainVerifier(onchain.New(rpcUrls, onchain.WithTimeout(rpcTimeout))))
+	}
+	tm, err := tokenmanager.New(context.Background(), ops...)
 	if err != nil {
 		log.Fatalf("failed to create token manager: %v", err)
 	}
@@ -94,17 +124,72 @@ func main() {
 		}
 	}
 
+	failed := false
+	warnings := 0
+
+	networkErrors := tm.ValidateNetworks(context.Background())
+	networkIds := make([]int64, 0, len(networkErrors))
+	for networkId := range networkErrors {
+		networkIds = append(networkIds, networkId)
+	}
+	sort.Slice(networkIds, func(i, j int) bool { return networkIds[i] < networkIds[j] })
+	for _, networkId := range networkIds {
+		f, w := report(fmt.Sprintf("network %d", networkId), networkErrors[networkId])
+		failed = failed || f
+		warnings += w
+	}
+
+	tagErrors := tm.ValidateTags(context.Background())
+	for _, tagId := range sortedUids(tagErrors) {
+		f, w := report(fmt.Sprintf("tag %s", tagId), tagErrors[tagId])
+		failed = failed || f
+		warnings += w
+	}
+
 	validationErrors := tm.ValidateTokens(context.Background())
-	if len(validationErrors) > 0 {
-		for tokenUid, errors := range validationErrors {
-			fmt.Printf("token %s has errors:\n", tokenUid)
-			for _, error := range errors {
-				fmt.Printf("  - %s\n", error)
-			}
-		}
+	for _, tokenUid := range sortedUids(validationErrors) {
+		f, w := report(fmt.Sprintf("token %s", tokenUid), validationErrors[tokenUid])
+		failed = failed || f
+		warnings += w
+	}
+	if failed {
 		os.Exit(1)
+	}
+	if warnings > 0 {
+		fmt.Printf("all networks, tags and tokens are valid (%d warnings)\n", warnings)
+	} else {
+		fmt.Printf("all networks, tags and tokens are valid\n")
+	}
+	fmt.Println("validation completed")
+}
+
+// report prints the validation errors of a network or a token,
+// it returns whether any of them fails the validation and the number of the warnings.
+func report(subject string, errors []error) (bool, int) {
+	failed := tokenmanager.HasErrors(errors)
+	if failed {
+		fmt.Printf("%s has errors:\n", subject)
 	} else {
-		fmt.Printf("all tokens are valid\n")
-		fmt.Println("validation completed")
+		fmt.Printf("%s has warnings:\n", subject)
+	}
+	warnings := 0
+	for _, error := range errors {
+		if tokenmanager.IsWarning(error) {
+			warnings++
+			fmt.Printf("  - warning: %s\n", error)
+		} else {
+			fmt.Printf("  - %s\n", error)
+		}
+	}
+	return failed, warnings
+}
+
+// sortedUids returns the token uids or the tag ids of the validation errors in order.
+func sortedUids(validationErrors map[string][]error) []string {
+	uids := make([]string, 0, len(validationErrors))
+	for uid := range validationErrors {
+		uids = append(uids, uid)
 	}
+	sort.Strings(uids)
+	return uids
 }